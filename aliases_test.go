@@ -0,0 +1,99 @@
+package env
+
+import (
+	"testing"
+)
+
+type aliasesConfig struct {
+	Host string `env:"DATABASE_HOST" aliases:"DB_HOST, PGHOST" deprecated:"true" required:"true"`
+	Port int    `env:"DATABASE_PORT" aliases:"DB_PORT"`
+}
+
+func TestAliases(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source map[string]string
+		exp    aliasesConfig
+		err    string
+	}{
+		{name: "name", source: map[string]string{"DATABASE_HOST": "a"}, exp: aliasesConfig{Host: "a"}},
+		{name: "first alias", source: map[string]string{"DB_HOST": "b"}, exp: aliasesConfig{Host: "b"}},
+		{name: "second alias", source: map[string]string{"PGHOST": "c"}, exp: aliasesConfig{Host: "c"}},
+		{name: "agreeing", source: map[string]string{"DATABASE_HOST": "a", "DB_HOST": "a", "PGHOST": "a"}, exp: aliasesConfig{Host: "a"}},
+		{name: "name conflicts with alias", source: map[string]string{"DATABASE_HOST": "a", "PGHOST": "c"}, err: "DATABASE_HOST and PGHOST are set to different values"},
+		{name: "aliases conflict", source: map[string]string{"DB_HOST": "b", "PGHOST": "c"}, err: "DB_HOST and PGHOST are set to different values"},
+		{name: "missing", source: map[string]string{}, err: "DATABASE_HOST environment configuration was missing"},
+		{name: "not deprecated", source: map[string]string{"DB_HOST": "b", "DB_PORT": "5432"}, exp: aliasesConfig{Host: "b", Port: 5432}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var config aliasesConfig
+			err := NewLoader(WithSource(mapSource(testCase.source))).Load(&config)
+			if testCase.err != "" {
+				ErrorNotNil(t, err)
+				Equals(t, testCase.err, err.Error())
+				return
+			}
+			ErrorNil(t, err)
+			Equals(t, testCase.exp, config)
+		})
+	}
+}
+
+func TestAliasesPrefix(t *testing.T) {
+	var config aliasesConfig
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"APP_PGHOST": "c", "PGHOST": "x"})),
+		WithPrefix("APP_"),
+	)
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "c", config.Host)
+}
+
+func TestDeprecationHandler(t *testing.T) {
+	var warnings [][2]string
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"APP_DB_HOST": "b", "APP_DB_PORT": "5432"})),
+		WithPrefix("APP_"),
+		WithDeprecationHandler(func(old, new string) {
+			warnings = append(warnings, [2]string{old, new})
+		}),
+	)
+
+	var config aliasesConfig
+	ErrorNil(t, l.Load(&config))
+	Equals(t, [][2]string{{"APP_DB_HOST", "APP_DATABASE_HOST"}}, warnings)
+
+	// Fields set from their current names aren't reported.
+	warnings = nil
+	l = NewLoader(
+		WithSource(mapSource(map[string]string{"DATABASE_HOST": "a"})),
+		WithDeprecationHandler(func(old, new string) {
+			warnings = append(warnings, [2]string{old, new})
+		}),
+	)
+	ErrorNil(t, l.Load(&config))
+	Equals(t, 0, len(warnings))
+}
+
+func TestInvalidDeprecatedTag(t *testing.T) {
+	config := struct {
+		Host string `env:"HOST" aliases:"OLD_HOST" deprecated:"yes"`
+	}{}
+
+	err := NewLoader(WithSource(mapSource(map[string]string{}))).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `invalid deprecated tag "yes": strconv.ParseBool: parsing "yes": invalid syntax`, err.Error())
+}
+
+func TestAliasesSetPrefix(t *testing.T) {
+	unsetEnvironment()
+	defer unsetEnvironment()
+	t.Setenv("APP_DB_HOST", "b")
+
+	var config aliasesConfig
+	ErrorNil(t, SetPrefix(&config, "APP_"))
+	Equals(t, "b", config.Host)
+}
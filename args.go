@@ -0,0 +1,92 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ParseArgs parses command-line arguments, such as os.Args[1:], into
+// a Source holding values for the fields of the struct behind v, keyed
+// by their env names with the given prefix applied, as a Loader looks
+// them up.  No flags need to be defined: arguments are matched to
+// fields using the same names as RegisterFlags.
+//
+// Values may be given as --name=value or --name value (with one dash
+// or two).  Bool fields are set to true by --name and to false by
+// --no-name, and repeating an argument for a slice field appends to
+// it.  Arguments that aren't flags are returned, in order, as are any
+// following "--".
+func ParseArgs(v interface{}, prefix string, args []string) (src MapSource, positional []string, err error) {
+	return std.parseArgs(v, prefix, args)
+}
+
+// ParseArgs is like the function of the same name, but matches
+// arguments to fields as the Loader reads them, with its tags and
+// names, and keys values with its prefix.
+func (l *Loader) ParseArgs(v interface{}, args []string) (src MapSource, positional []string, err error) {
+	return l.parseArgs(v, l.prefix, args)
+}
+
+func (l *Loader) parseArgs(v interface{}, prefix string, args []string) (src MapSource, positional []string, err error) {
+	tagged, err := l.fields(v, "")
+	if err != nil {
+		return
+	}
+
+	byName := map[string]field{}
+	var names []string
+	for _, f := range tagged {
+		if name, ok := flagName(f); ok {
+			byName[name] = f
+			names = append(names, "--"+name)
+		}
+	}
+
+	src = MapSource{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+		f, ok := byName[name]
+		switch {
+		case ok && f.sf.Type.Kind() == reflect.Bool:
+			if !hasValue {
+				value = "true"
+			}
+		case ok && !hasValue:
+			if i+1 == len(args) {
+				return nil, nil, fmt.Errorf("argument %s needs a value", arg)
+			}
+			i++
+			value = args[i]
+		case !ok && strings.HasPrefix(name, "no-") && !hasValue:
+			if f, ok = byName[strings.TrimPrefix(name, "no-")]; ok && f.sf.Type.Kind() == reflect.Bool {
+				value = "false"
+				break
+			}
+			fallthrough
+		case !ok:
+			if s := suggest("--"+name, names); s != "" {
+				return nil, nil, fmt.Errorf("unknown argument --%s (did you mean %s?)", name, s)
+			}
+			return nil, nil, fmt.Errorf("unknown argument --%s", name)
+		}
+
+		key := prefix + f.name
+		if prev, ok := src[key]; ok && f.isList() {
+			value = prev + f.delimiter + value
+		}
+		src[key] = value
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package env
+
+import (
+	"testing"
+	"time"
+)
+
+type argsConfig struct {
+	DBHost  string        `env:"DB_HOST"`
+	Port    int           `env:"PORT" flag:"listen-port"`
+	Offset  int           `env:"OFFSET"`
+	Timeout time.Duration `env:"TIMEOUT" default:"1s"`
+	Verbose bool          `env:"VERBOSE" default:"true"`
+	Cache   bool          `env:"CACHE"`
+	Peers   []string      `env:"PEERS" delimiter:";"`
+	Hidden  string        `env:"HIDDEN" flag:"-"`
+}
+
+func TestParseArgs(t *testing.T) {
+	args := []string{
+		"run",
+		"--db-host=x",
+		"-listen-port", "8080",
+		"--offset", "-1",
+		"--no-verbose",
+		"--cache",
+		"--peers", "a",
+		"file.txt",
+		"--peers=b;c",
+		"--",
+		"--db-host=y",
+	}
+
+	src, positional, err := ParseArgs(&argsConfig{}, "APP_", args)
+	ErrorNil(t, err)
+	Equals(t, []string{"run", "file.txt", "--db-host=y"}, positional)
+	Equals(t, MapSource{
+		"APP_DB_HOST": "x",
+		"APP_PORT":    "8080",
+		"APP_OFFSET":  "-1",
+		"APP_VERBOSE": "false",
+		"APP_CACHE":   "true",
+		"APP_PEERS":   "a;b;c",
+	}, src)
+
+	var config argsConfig
+	ErrorNil(t, NewLoader(WithSource(src), WithPrefix("APP_")).Load(&config))
+	Equals(t, argsConfig{
+		DBHost:  "x",
+		Port:    8080,
+		Offset:  -1,
+		Timeout: time.Second,
+		Cache:   true,
+		Peers:   []string{"a", "b", "c"},
+	}, config)
+}
+
+func TestParseArgsRepeated(t *testing.T) {
+	src, _, err := ParseArgs(&argsConfig{}, "", []string{"--db-host=a", "--db-host=b", "--cache=false", "--cache"})
+	ErrorNil(t, err)
+	Equals(t, MapSource{"DB_HOST": "b", "CACHE": "true"}, src)
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "missing value", args: []string{"--db-host"}, err: "argument --db-host needs a value"},
+		{name: "unknown", args: []string{"--colour=red"}, err: "unknown argument --colour"},
+		{name: "typo", args: []string{"--db-hots", "x"}, err: "unknown argument --db-hots (did you mean --db-host?)"},
+		{name: "skipped", args: []string{"--hidden=x"}, err: "unknown argument --hidden"},
+		{name: "negated non-bool", args: []string{"--no-offset"}, err: "unknown argument --no-offset (did you mean --offset?)"},
+		{name: "negated with value", args: []string{"--no-cache=true"}, err: "unknown argument --no-cache (did you mean --cache?)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, err := ParseArgs(&argsConfig{}, "", testCase.args)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestParseArgsNotStruct(t *testing.T) {
+	_, _, err := ParseArgs(1, "", nil)
+	ErrorNotNil(t, err)
+}
+
+func TestLoaderParseArgs(t *testing.T) {
+	config := struct {
+		DBHost string `cfg:"HOST"`
+		DB     struct {
+			Name string
+		}
+	}{}
+
+	opts := []Option{WithTags(Tags{Env: "cfg"}), WithAutoNames(), WithPrefix("APP_")}
+	src, positional, err := NewLoader(opts...).ParseArgs(&config, []string{"--host=h", "--db-name", "n", "x"})
+	ErrorNil(t, err)
+	Equals(t, []string{"x"}, positional)
+	Equals(t, MapSource{"APP_HOST": "h", "APP_DB_NAME": "n"}, src)
+
+	ErrorNil(t, NewLoader(append(opts, WithSource(src))...).Load(&config))
+	Equals(t, "h", config.DBHost)
+	Equals(t, "n", config.DB.Name)
+}
@@ -0,0 +1,15 @@
+// Command envcheck reports mistakes in the struct tags read by
+// github.com/codingconcepts/env.  It can be run directly, or by go vet:
+//
+//	go install github.com/codingconcepts/env/cmd/envcheck@latest
+//	go vet -vettool=$(which envcheck) ./...
+package main
+
+import (
+	"github.com/codingconcepts/env/envcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(envcheck.Analyzer)
+}
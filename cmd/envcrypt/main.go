@@ -0,0 +1,76 @@
+// Command envcrypt encrypts values for github.com/codingconcepts/env
+// loaders configured with env.WithDecryptionKey, so that they can be
+// committed in .env files.
+//
+// The key is read, base64 encoded, from the ENV_ENCRYPTION_KEY
+// environment variable, and the value is read from standard input so
+// that it isn't recorded in shell history:
+//
+//	$ export ENV_ENCRYPTION_KEY=$(envcrypt -generate)
+//	$ echo -n hunter2 | envcrypt
+//	enc:...
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/codingconcepts/env"
+)
+
+// keyVar is the environment variable holding the key.
+const keyVar = "ENV_ENCRYPTION_KEY"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("envcrypt: ")
+
+	generate := flag.Bool("generate", false, "print a new random 32 byte key and exit")
+	flag.Parse()
+
+	if err := run(*generate, os.Getenv(keyVar), os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(generate bool, encodedKey string, in io.Reader, out io.Writer) error {
+	if generate {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(out, base64.StdEncoding.EncodeToString(key))
+		return err
+	}
+
+	if encodedKey == "" {
+		return fmt.Errorf("%s is not set", keyVar)
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return errors.New(keyVar + " is not valid base64")
+	}
+
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	// A single trailing newline, as added by echo, isn't part of the
+	// value.
+	value := strings.TrimSuffix(string(b), "\n")
+	encrypted, err := env.Encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, encrypted)
+	return err
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/codingconcepts/env"
+)
+
+func TestRun(t *testing.T) {
+	var key bytes.Buffer
+	if err := run(true, "", nil, &key); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	encodedKey := strings.TrimSpace(key.String())
+	if err := run(false, encodedKey, strings.NewReader("hunter2\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := base64.StdEncoding.DecodeString(encodedKey)
+	value, err := env.Decrypt(raw, strings.TrimSpace(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "hunter2" {
+		t.Fatalf("unexpected value %q", value)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  string
+		err  string
+	}{
+		{name: "missing key", key: "", err: "ENV_ENCRYPTION_KEY is not set"},
+		{name: "invalid key", key: "!", err: "ENV_ENCRYPTION_KEY is not valid base64"},
+		{name: "short key", key: "YWJj", err: "crypto/aes: invalid key size 3"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := run(false, testCase.key, strings.NewReader("x"), new(bytes.Buffer))
+			if err == nil || err.Error() != testCase.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
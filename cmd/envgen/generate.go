@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codingconcepts/env/internal/structtag"
+	"github.com/codingconcepts/env/internal/typeinfo"
+	"golang.org/x/tools/go/packages"
+)
+
+// generator builds the source of a LoadEnv method.
+type generator struct {
+	pkg     *packages.Package
+	buf     bytes.Buffer
+	imports map[string]bool
+
+	// fields is the number of fields set, and returned is true if
+	// the method has already returned.
+	fields   int
+	returned bool
+}
+
+// generate returns the formatted source of a file declaring a LoadEnv
+// method for the named struct type in pkg.
+func generate(pkg *packages.Package, typeName string) ([]byte, error) {
+	st, err := findStruct(pkg, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{pkg: pkg, imports: map[string]bool{}}
+
+	g.printf("// LoadEnv sets the fields of c from the values returned by lookup, in\n")
+	g.printf("// the same way as env.Set does from environment variables.\n")
+	g.printf("func (c *%s) LoadEnv(lookup func(string) (string, bool)) error {\n", typeName)
+	header := g.buf.Len()
+	if err = g.structFields(st); err != nil {
+		return nil, err
+	}
+	if g.fields > 0 {
+		body := append([]byte("var v string\nvar ok bool\n"), g.buf.Bytes()[header:]...)
+		g.buf.Truncate(header)
+		g.buf.Write(body)
+	}
+	if !g.returned {
+		g.printf("return nil\n")
+	}
+	g.printf("}\n")
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by envgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg.Name)
+	if len(g.imports) > 0 {
+		var paths []string
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		fmt.Fprintf(&out, "import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "%q\n", path)
+		}
+		fmt.Fprintf(&out, ")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+func findStruct(pkg *packages.Package, typeName string) (st *ast.StructType, err error) {
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				return st == nil
+			}
+			st, _ = ts.Type.(*ast.StructType)
+			return false
+		})
+	}
+
+	if st == nil {
+		return nil, fmt.Errorf("struct type %s not found in package %s", typeName, pkg.PkgPath)
+	}
+	return
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) structFields(st *ast.StructType) error {
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return err
+		}
+		tag := reflect.StructTag(raw)
+
+		envTag, ok := tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		envName, opts, err := parseEnvTag(envTag, tag)
+		if envName == "-" {
+			continue
+		}
+
+		t := g.pkg.TypesInfo.TypeOf(f.Type)
+		for _, name := range fieldNames(f) {
+			// SetPrefix fails on reaching a field with an invalid tag
+			// or an unexported field, so nothing after it can be set.
+			if err != nil {
+				g.printf("return errors.New(%q)\n", err.Error())
+				g.imports["errors"] = true
+				g.returned = true
+				return nil
+			}
+			if envName == "" {
+				return fmt.Errorf("field %s: env tag has no name", name)
+			}
+			if !ast.IsExported(name) {
+				g.printf("return errors.New(%q)\n", fmt.Sprintf("field '%s' cannot be set", name))
+				g.imports["errors"] = true
+				g.returned = true
+				return nil
+			}
+
+			for _, key := range unsupportedTags {
+				if _, ok := tag.Lookup(key); ok {
+					return fmt.Errorf("field %s: %s tag is not supported by envgen", name, key)
+				}
+			}
+
+			if err = g.field(name, t, envName, opts, tag); err != nil {
+				return fmt.Errorf("field %s: %v", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// unsupportedTags are read by env.Set but not by generated loaders,
+// which would silently behave differently if they were ignored.
+var unsupportedTags = []string{"aliases", "requiredIf", "requiredUnless", "oneOf", "exclusive"}
+
+func fieldNames(f *ast.Field) []string {
+	var names []string
+	for _, n := range f.Names {
+		names = append(names, n.Name)
+	}
+	if len(names) > 0 {
+		return names
+	}
+
+	// Embedded fields are named after their type.
+	t := f.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	}
+	return nil
+}
+
+func (g *generator) field(name string, t types.Type, envName string, opts structtag.Options, tag reflect.StructTag) error {
+	g.fields++
+	g.printf("\n// %s\n", name)
+	g.printf("v, ok = lookup(%q)\n", envName)
+
+	if d, ok := tag.Lookup("default"); ok {
+		g.printf("if !ok {\n")
+		g.printf("v, ok = %q, true\n", d)
+		g.printf("}\n")
+	} else {
+		var msg string
+		reqTag, ok := tag.Lookup("required")
+		required, err := strconv.ParseBool(reqTag)
+		switch {
+		case ok && err != nil:
+			msg = fmt.Sprintf("invalid required tag %q: %v", reqTag, err)
+		case opts.Required || ok && required:
+			msg = fmt.Sprintf("%s environment configuration was missing", envName)
+		}
+
+		if msg != "" {
+			g.printf("if !ok {\n")
+			g.printf("return errors.New(%q)\n", msg)
+			g.printf("}\n")
+			g.imports["errors"] = true
+		}
+	}
+
+	g.printf("if ok {\n")
+	if opts.File {
+		g.printf("b, err := os.ReadFile(v)\n")
+		g.printf("if err != nil {\n")
+		g.printf("return fmt.Errorf(\"error reading file for %%q: %%v\", %q, err)\n", name)
+		g.printf("}\n")
+		g.printf("v = string(b)\n")
+		g.imports["fmt"] = true
+		g.imports["os"] = true
+	}
+	if opts.NotEmpty {
+		g.printf("if v == \"\" {\n")
+		g.printf("return errors.New(%q)\n", fmt.Sprintf("%s environment configuration was empty", envName))
+		g.printf("}\n")
+		g.imports["errors"] = true
+	}
+	if err := g.set(name, t, opts, tag); err != nil {
+		return err
+	}
+	g.printf("}\n")
+
+	return nil
+}
+
+// set writes the code to parse v into field c.name, which mirrors
+// the behaviour of the env package's field setters for its type.
+func (g *generator) set(name string, t types.Type, opts structtag.Options, tag reflect.StructTag) error {
+	if typeinfo.IsSetter(t) {
+		p := t.(*types.Pointer)
+		g.printf("c.%s = new(%s)\n", name, g.typeString(p.Elem()))
+		g.printf("if err := c.%s.Set(v); err != nil {\n", name)
+		g.printf("return fmt.Errorf(\"error in custom setter: %%v\", err)\n")
+		g.printf("}\n")
+		g.imports["fmt"] = true
+		return nil
+	}
+
+	if s, ok := t.(*types.Slice); ok {
+		return g.setSlice(name, s, opts, tag)
+	}
+
+	parse, conv, err := g.parser(t, "v")
+	if err != nil {
+		return err
+	}
+	if parse == "" {
+		g.printf("c.%s = %s\n", name, conv)
+		return nil
+	}
+	g.printf("x, err := %s\n", parse)
+	g.printf("if err != nil {\n")
+	g.printf("return fmt.Errorf(\"error setting %%q: %%v\", %q, err)\n", name)
+	g.printf("}\n")
+	g.printf("c.%s = %s\n", name, conv)
+	g.imports["fmt"] = true
+
+	return nil
+}
+
+func (g *generator) setSlice(name string, s *types.Slice, opts structtag.Options, tag reflect.StructTag) error {
+	// []uint8 and []byte hold the raw value.
+	if b, ok := s.Elem().(*types.Basic); ok && b.Kind() == types.Uint8 {
+		g.printf("c.%s = []byte(v)\n", name)
+		return nil
+	}
+
+	if !isListElem(s.Elem()) {
+		return fmt.Errorf("%s is not supported", g.typeString(s))
+	}
+
+	delimiter, ok := tag.Lookup("delimiter")
+	switch {
+	case opts.HasDelimiter:
+		delimiter = opts.Delimiter
+	case !ok:
+		delimiter = ","
+	}
+
+	parse, conv, err := g.parser(s.Elem(), "item")
+	if err != nil {
+		return err
+	}
+
+	g.printf("var items []string\n")
+	g.printf("for _, item := range strings.Split(v, %q) {\n", delimiter)
+	g.printf("if item = strings.Trim(item, \" \"); item != \"\" {\n")
+	g.printf("items = append(items, item)\n")
+	g.printf("}\n")
+	g.printf("}\n")
+	g.printf("if len(items) > 0 {\n")
+	g.printf("s := make(%s, len(items))\n", g.typeString(s))
+	g.printf("for i, item := range items {\n")
+	if parse == "" {
+		g.printf("s[i] = %s\n", conv)
+	} else {
+		// Items that fail to parse are left as zero values.
+		g.printf("if x, err := %s; err == nil {\n", parse)
+		g.printf("s[i] = %s\n", conv)
+		g.printf("}\n")
+	}
+	g.printf("}\n")
+	g.printf("c.%s = s\n", name)
+	g.printf("}\n")
+	g.imports["strings"] = true
+
+	return nil
+}
+
+// parseEnvTag parses an env tag as the env package does, returning the
+// error SetPrefix would if the options are unknown or contradict the
+// field's standalone tags.
+func parseEnvTag(envTag string, tag reflect.StructTag) (name string, opts structtag.Options, err error) {
+	if name, opts, err = structtag.ParseEnv(envTag); err != nil {
+		return
+	}
+	return name, opts, opts.Conflict(tag, "required", "secret", "delimiter")
+}
+
+// parser returns an expression parsing the named string variable
+// into x (or an empty string if there's nothing to parse), and an
+// expression converting x into a value of type t.
+func (g *generator) parser(t types.Type, from string) (parse, conv string, err error) {
+	typ := g.typeString(t)
+
+	if typeinfo.IsDuration(t) {
+		g.imports["time"] = true
+		return fmt.Sprintf("time.ParseDuration(%s)", from), "x", nil
+	}
+
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return "", "", fmt.Errorf("%s is not supported", typ)
+	}
+
+	// The parsed value only needs converting if it's not already of
+	// the field's type.
+	var parsed string
+	switch {
+	case b.Kind() == types.String:
+		if typ == "string" {
+			return "", from, nil
+		}
+		return "", fmt.Sprintf("%s(%s)", typ, from), nil
+	case b.Kind() == types.Bool:
+		parse, parsed = fmt.Sprintf("strconv.ParseBool(%s)", from), "bool"
+	case b.Info()&types.IsInteger != 0 && b.Info()&types.IsUnsigned != 0 && b.Kind() != types.Uintptr:
+		parse, parsed = fmt.Sprintf("strconv.ParseUint(%s, 0, 64)", from), "uint64"
+	case b.Info()&types.IsInteger != 0 && b.Info()&types.IsUnsigned == 0:
+		parse, parsed = fmt.Sprintf("strconv.ParseInt(%s, 0, 64)", from), "int64"
+	case b.Kind() == types.Float32 || b.Kind() == types.Float64:
+		parse, parsed = fmt.Sprintf("strconv.ParseFloat(%s, 64)", from), "float64"
+	default:
+		return "", "", fmt.Errorf("%s is not supported", typ)
+	}
+
+	g.imports["strconv"] = true
+	if typ == parsed {
+		return parse, "x", nil
+	}
+	return parse, fmt.Sprintf("%s(x)", typ), nil
+}
+
+// typeString returns the name of t as written in the generated file,
+// importing its package if necessary.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p.Path() == g.pkg.PkgPath {
+			return ""
+		}
+		g.imports[p.Path()] = true
+		return p.Name()
+	})
+}
+
+// isListElem mirrors the slice types supported by the env package,
+// which must be unnamed slices of predeclared types or time.Duration.
+func isListElem(t types.Type) bool {
+	if typeinfo.IsDuration(t) {
+		return true
+	}
+
+	b, ok := t.(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch b.Kind() {
+	case types.String, types.Bool,
+		types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64:
+		return true
+	}
+	return false
+}
+
+// outputName returns the default name of the file generated for the
+// given type.
+func outputName(typeName string) string {
+	return strings.ToLower(typeName) + "_env.go"
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+
+	pkg, err := load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := generate(pkg, "Config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := os.ReadFile(filepath.Join(dir, outputName("Config")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(exp, act) {
+		t.Fatalf("%s is out of date, run go generate ./...", outputName("Config"))
+	}
+}
+
+func TestGenerateUnsupportedType(t *testing.T) {
+	pkg, err := load(filepath.Join("testdata", "unsupported"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = generate(pkg, "Config")
+	if err == nil || err.Error() != "field Chan: chan int is not supported" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGenerateMissingType(t *testing.T) {
+	pkg, err := load(filepath.Join("testdata", "unsupported"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = generate(pkg, "Missing")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
+func TestGenerateNoName(t *testing.T) {
+	pkg, err := load(filepath.Join("testdata", "unsupported"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = generate(pkg, "NoName")
+	if err == nil || err.Error() != "field Port: env tag has no name" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGenerateUnsupportedTags(t *testing.T) {
+	pkg, err := load(filepath.Join("testdata", "unsupported"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		typ string
+		err string
+	}{
+		{typ: "Aliases", err: "field Port: aliases tag is not supported by envgen"},
+		{typ: "RequiredIf", err: "field Cert: requiredIf tag is not supported by envgen"},
+		{typ: "RequiredUnless", err: "field Token: requiredUnless tag is not supported by envgen"},
+		{typ: "OneOf", err: "field Token: oneOf tag is not supported by envgen"},
+		{typ: "Exclusive", err: "field Token: exclusive tag is not supported by envgen"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.typ, func(t *testing.T) {
+			_, err := generate(pkg, testCase.typ)
+			if err == nil || err.Error() != testCase.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
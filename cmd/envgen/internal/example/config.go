@@ -0,0 +1,61 @@
+// Package example holds a struct with a generated loader, which is
+// tested against env.SetPrefix to ensure that the two agree.
+package example
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+//go:generate go run github.com/codingconcepts/env/cmd/envgen -type Config
+
+// Config uses each of the field types and tags supported by envgen.
+type Config struct {
+	Bool      bool            `env:"BOOL"`
+	Int       int             `env:"INT" default:"1"`
+	Int8      int8            `env:"INT8"`
+	Int16     int16           `env:"INT16"`
+	Int32     int32           `env:"INT32"`
+	Int64     int64           `env:"INT64"`
+	Uint      uint            `env:"UINT"`
+	Uint8     uint8           `env:"UINT8"`
+	Uint16    uint16          `env:"UINT16"`
+	Uint32    uint32          `env:"UINT32"`
+	Uint64    uint64          `env:"UINT64"`
+	Float32   float32         `env:"FLOAT32"`
+	Float64   float64         `env:"FLOAT64"`
+	String    string          `env:"STRING" required:"true"`
+	Custom    customInt       `env:"CUSTOM"`
+	Duration  time.Duration   `env:"DURATION" default:"1s"`
+	Bytes     []byte          `env:"BYTES"`
+	Strings   []string        `env:"STRINGS"`
+	Bools     []bool          `env:"BOOLS"`
+	Ints      []int           `env:"INTS" delimiter:";"`
+	Uints     []uint64        `env:"UINTS"`
+	Floats    []float32       `env:"FLOATS"`
+	Durations []time.Duration `env:"DURATIONS" default:"1s, 2s"`
+	Setter    *upper          `env:"SETTER"`
+	internal  string          `env:"-"`
+	Invalid   string          `env:"INVALID" required:"yes"`
+	Options   []string        `env:"OPTIONS,required,notEmpty,delimiter=;"`
+	File      string          `env:"FILE,file" default:"testdata/file.txt"`
+	Untagged  chan int
+}
+
+type customInt int16
+
+var errEmpty = errors.New("value is empty")
+
+// upper implements env.Setter.
+type upper struct {
+	Value string
+}
+
+func (u *upper) Set(s string) error {
+	if s == "" {
+		return errEmpty
+	}
+	u.Value = strings.ToUpper(s)
+	return nil
+}
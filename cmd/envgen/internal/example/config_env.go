@@ -0,0 +1,368 @@
+// Code generated by envgen; DO NOT EDIT.
+
+package example
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadEnv sets the fields of c from the values returned by lookup, in
+// the same way as env.Set does from environment variables.
+func (c *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	var v string
+	var ok bool
+
+	// Bool
+	v, ok = lookup("BOOL")
+	if ok {
+		x, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Bool", err)
+		}
+		c.Bool = x
+	}
+
+	// Int
+	v, ok = lookup("INT")
+	if !ok {
+		v, ok = "1", true
+	}
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Int", err)
+		}
+		c.Int = int(x)
+	}
+
+	// Int8
+	v, ok = lookup("INT8")
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Int8", err)
+		}
+		c.Int8 = int8(x)
+	}
+
+	// Int16
+	v, ok = lookup("INT16")
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Int16", err)
+		}
+		c.Int16 = int16(x)
+	}
+
+	// Int32
+	v, ok = lookup("INT32")
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Int32", err)
+		}
+		c.Int32 = int32(x)
+	}
+
+	// Int64
+	v, ok = lookup("INT64")
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Int64", err)
+		}
+		c.Int64 = x
+	}
+
+	// Uint
+	v, ok = lookup("UINT")
+	if ok {
+		x, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Uint", err)
+		}
+		c.Uint = uint(x)
+	}
+
+	// Uint8
+	v, ok = lookup("UINT8")
+	if ok {
+		x, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Uint8", err)
+		}
+		c.Uint8 = uint8(x)
+	}
+
+	// Uint16
+	v, ok = lookup("UINT16")
+	if ok {
+		x, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Uint16", err)
+		}
+		c.Uint16 = uint16(x)
+	}
+
+	// Uint32
+	v, ok = lookup("UINT32")
+	if ok {
+		x, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Uint32", err)
+		}
+		c.Uint32 = uint32(x)
+	}
+
+	// Uint64
+	v, ok = lookup("UINT64")
+	if ok {
+		x, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Uint64", err)
+		}
+		c.Uint64 = x
+	}
+
+	// Float32
+	v, ok = lookup("FLOAT32")
+	if ok {
+		x, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Float32", err)
+		}
+		c.Float32 = float32(x)
+	}
+
+	// Float64
+	v, ok = lookup("FLOAT64")
+	if ok {
+		x, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Float64", err)
+		}
+		c.Float64 = x
+	}
+
+	// String
+	v, ok = lookup("STRING")
+	if !ok {
+		return errors.New("STRING environment configuration was missing")
+	}
+	if ok {
+		c.String = v
+	}
+
+	// Custom
+	v, ok = lookup("CUSTOM")
+	if ok {
+		x, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Custom", err)
+		}
+		c.Custom = customInt(x)
+	}
+
+	// Duration
+	v, ok = lookup("DURATION")
+	if !ok {
+		v, ok = "1s", true
+	}
+	if ok {
+		x, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("error setting %q: %v", "Duration", err)
+		}
+		c.Duration = x
+	}
+
+	// Bytes
+	v, ok = lookup("BYTES")
+	if ok {
+		c.Bytes = []byte(v)
+	}
+
+	// Strings
+	v, ok = lookup("STRINGS")
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]string, len(items))
+			for i, item := range items {
+				s[i] = item
+			}
+			c.Strings = s
+		}
+	}
+
+	// Bools
+	v, ok = lookup("BOOLS")
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]bool, len(items))
+			for i, item := range items {
+				if x, err := strconv.ParseBool(item); err == nil {
+					s[i] = x
+				}
+			}
+			c.Bools = s
+		}
+	}
+
+	// Ints
+	v, ok = lookup("INTS")
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ";") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]int, len(items))
+			for i, item := range items {
+				if x, err := strconv.ParseInt(item, 0, 64); err == nil {
+					s[i] = int(x)
+				}
+			}
+			c.Ints = s
+		}
+	}
+
+	// Uints
+	v, ok = lookup("UINTS")
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]uint64, len(items))
+			for i, item := range items {
+				if x, err := strconv.ParseUint(item, 0, 64); err == nil {
+					s[i] = x
+				}
+			}
+			c.Uints = s
+		}
+	}
+
+	// Floats
+	v, ok = lookup("FLOATS")
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]float32, len(items))
+			for i, item := range items {
+				if x, err := strconv.ParseFloat(item, 64); err == nil {
+					s[i] = float32(x)
+				}
+			}
+			c.Floats = s
+		}
+	}
+
+	// Durations
+	v, ok = lookup("DURATIONS")
+	if !ok {
+		v, ok = "1s, 2s", true
+	}
+	if ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]time.Duration, len(items))
+			for i, item := range items {
+				if x, err := time.ParseDuration(item); err == nil {
+					s[i] = x
+				}
+			}
+			c.Durations = s
+		}
+	}
+
+	// Setter
+	v, ok = lookup("SETTER")
+	if ok {
+		c.Setter = new(upper)
+		if err := c.Setter.Set(v); err != nil {
+			return fmt.Errorf("error in custom setter: %v", err)
+		}
+	}
+
+	// Invalid
+	v, ok = lookup("INVALID")
+	if !ok {
+		return errors.New("invalid required tag \"yes\": strconv.ParseBool: parsing \"yes\": invalid syntax")
+	}
+	if ok {
+		c.Invalid = v
+	}
+
+	// Options
+	v, ok = lookup("OPTIONS")
+	if !ok {
+		return errors.New("OPTIONS environment configuration was missing")
+	}
+	if ok {
+		if v == "" {
+			return errors.New("OPTIONS environment configuration was empty")
+		}
+		var items []string
+		for _, item := range strings.Split(v, ";") {
+			if item = strings.Trim(item, " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			s := make([]string, len(items))
+			for i, item := range items {
+				s[i] = item
+			}
+			c.Options = s
+		}
+	}
+
+	// File
+	v, ok = lookup("FILE")
+	if !ok {
+		v, ok = "testdata/file.txt", true
+	}
+	if ok {
+		b, err := os.ReadFile(v)
+		if err != nil {
+			return fmt.Errorf("error reading file for %q: %v", "File", err)
+		}
+		v = string(b)
+		c.File = v
+	}
+	return nil
+}
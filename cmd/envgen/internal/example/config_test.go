@@ -0,0 +1,99 @@
+package example
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codingconcepts/env"
+)
+
+func TestLoadEnvMatchesSetPrefix(t *testing.T) {
+	required := map[string]string{"STRING": "s", "INVALID": "i", "OPTIONS": "a;b"}
+
+	testCases := []struct {
+		name  string
+		env   map[string]string
+		unset []string
+	}{
+		{name: "defaults", env: map[string]string{}},
+		{name: "values", env: map[string]string{
+			"BOOL": "true", "INT": "-1", "INT8": "0x7f", "INT16": "0b101", "INT32": "0o17", "INT64": "1_000",
+			"UINT": "1", "UINT8": "255", "UINT16": "2", "UINT32": "3", "UINT64": "4",
+			"FLOAT32": "1.5", "FLOAT64": "-2.5e3", "CUSTOM": "7", "DURATION": "1h2m",
+			"BYTES": "abc", "STRINGS": "a, b,, c", "BOOLS": "true,f", "INTS": "1;2;3",
+			"UINTS": "1,2", "FLOATS": "1.5,2", "DURATIONS": "1m,2h", "SETTER": "hello",
+		}},
+		{name: "overflow", env: map[string]string{"INT8": "1000", "UINT8": "1000", "FLOAT32": "1e300"}},
+		{name: "invalid list items", env: map[string]string{"INTS": "1;x;3", "FLOATS": "1e999", "BOOLS": "yes", "DURATIONS": "1s,x"}},
+		{name: "empty list", env: map[string]string{"STRINGS": " , "}},
+		{name: "invalid bool", env: map[string]string{"BOOL": "yes"}},
+		{name: "invalid int", env: map[string]string{"INT": "one"}},
+		{name: "invalid uint", env: map[string]string{"UINT": "-1"}},
+		{name: "invalid float", env: map[string]string{"FLOAT64": "x"}},
+		{name: "invalid duration", env: map[string]string{"DURATION": "1x"}},
+		{name: "setter error", env: map[string]string{"SETTER": ""}},
+		{name: "missing required", unset: []string{"STRING"}},
+		{name: "invalid required", unset: []string{"INVALID"}},
+		{name: "missing required option", unset: []string{"OPTIONS"}},
+		{name: "empty", env: map[string]string{"OPTIONS": ""}},
+		{name: "file", env: map[string]string{"FILE": "testdata/other.txt"}},
+		{name: "missing file", env: map[string]string{"FILE": "testdata/missing.txt"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			vars := map[string]string{}
+			for k, v := range required {
+				vars[k] = v
+			}
+			for k, v := range testCase.env {
+				vars[k] = v
+			}
+			for _, k := range testCase.unset {
+				delete(vars, k)
+			}
+
+			for _, k := range envNames() {
+				os.Unsetenv("EXAMPLE_" + k)
+				if v, ok := vars[k]; ok {
+					os.Setenv("EXAMPLE_"+k, v)
+				}
+			}
+
+			var exp Config
+			expErr := env.SetPrefix(&exp, "EXAMPLE_")
+
+			var act Config
+			actErr := act.LoadEnv(func(k string) (string, bool) {
+				return os.LookupEnv("EXAMPLE_" + k)
+			})
+
+			if errString(expErr) != errString(actErr) {
+				t.Fatalf("\nexp err:\t%v\ngot err:\t%v", expErr, actErr)
+			}
+			if !reflect.DeepEqual(exp, act) {
+				t.Fatalf("\nexp:\t%+v\ngot:\t%+v", exp, act)
+			}
+		})
+	}
+}
+
+func envNames() (names []string) {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag, ok := t.Field(i).Tag.Lookup("env"); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,87 @@
+// Command envgen generates reflection-free loaders for structs tagged
+// for github.com/codingconcepts/env, for use with go generate:
+//
+//	//go:generate go run github.com/codingconcepts/env/cmd/envgen -type Config
+//
+// For a type Config, it writes a config_env.go file declaring:
+//
+//	func (c *Config) LoadEnv(lookup func(string) (string, bool)) error
+//
+// which reads the same "env", "default", "required" and "delimiter"
+// tags (including options in the env tag, such as env:"PORT,required")
+// and behaves in the same way as env.Set, taking its values from lookup
+// rather than the environment.  As it doesn't derive names, every env
+// tag must have one.  Pass os.LookupEnv to read from
+// the environment, or wrap it to apply a prefix.  Fields of types that
+// env.Set can't populate, and fields with "aliases", "requiredIf",
+// "requiredUnless", "oneOf" or "exclusive" tags, are reported when
+// generating, rather than when loading.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("envgen: ")
+
+	typeName := flag.String("type", "", "name of the struct type to generate a loader for")
+	output := flag.String("output", "", "output file name; default <type>_env.go")
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	if *output == "" {
+		*output = filepath.Join(dir, outputName(*typeName))
+	}
+
+	if err := run(dir, *typeName, *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(dir, typeName, output string) error {
+	pkg, err := load(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, typeName)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
+
+func load(dir string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir:  dir,
+	}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected 1 package in %s, found %d", dir, len(pkgs))
+	}
+
+	return pkgs[0], nil
+}
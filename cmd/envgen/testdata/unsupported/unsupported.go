@@ -0,0 +1,30 @@
+package unsupported
+
+type Config struct {
+	Chan chan int `env:"CHAN"`
+}
+
+type NoName struct {
+	Port int `env:",required"`
+}
+
+type Aliases struct {
+	Port int `env:"PORT" aliases:"HTTP_PORT"`
+}
+
+type RequiredIf struct {
+	TLS  bool   `env:"TLS"`
+	Cert string `env:"CERT" requiredIf:"TLS"`
+}
+
+type RequiredUnless struct {
+	Token string `env:"TOKEN" requiredUnless:"PASSWORD"`
+}
+
+type OneOf struct {
+	Token string `env:"TOKEN" oneOf:"auth"`
+}
+
+type Exclusive struct {
+	Token string `env:"TOKEN" exclusive:"auth"`
+}
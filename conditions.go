@@ -0,0 +1,126 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// condition is a requirement on the value of another field or key,
+// written as NAME=value, or just NAME if it need only be set.
+type condition struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// parseCondition parses the value of a requiredIf or requiredUnless
+// tag.
+func parseCondition(t reflect.StructField, tag string) (c *condition, err error) {
+	condTag, ok := t.Tag.Lookup(tag)
+	if !ok {
+		return nil, nil
+	}
+
+	c = &condition{}
+	c.name, c.value, c.hasValue = strings.Cut(condTag, "=")
+	if c.name = strings.TrimSpace(c.name); c.name == "" {
+		return nil, fmt.Errorf("invalid %s tag %q: expected NAME or NAME=value", tag, condTag)
+	}
+	return
+}
+
+// holds returns true if the condition is met by the loaded fields.  If
+// the condition names one of the fields, it's met if the field was set
+// (from a default or otherwise) to the given value, which is parsed in
+// the same way as the field's own.  Otherwise, the key is looked up in
+// the source.
+func (l *Loader) holds(c *condition, prefix string, fs []field, origins []origin, v reflect.Value) bool {
+	for i, f := range fs {
+		if f.name != c.name {
+			continue
+		}
+		if origins[i] == notSet || !c.hasValue {
+			return origins[i] != notSet
+		}
+
+		fv := v.FieldByIndex(f.index)
+		want := reflect.New(fv.Type()).Elem()
+		if err := f.set(want, c.value); err != nil {
+			return formatValue(f, fv) == c.value
+		}
+		return reflect.DeepEqual(fv.Interface(), want.Interface())
+	}
+
+	value, ok := l.source.Lookup(prefix + c.name)
+	return ok && (!c.hasValue || value == c.value)
+}
+
+// checkConditions checks the conditional requirements of the loaded
+// fields, along with their oneOf and exclusive groups, where origins
+// records where each field's value came from.
+func (l *Loader) checkConditions(prefix string, fs []field, origins []origin, v reflect.Value) error {
+	var errs []error
+	oneOf, exclusive := map[string][]int{}, map[string][]int{}
+	var groups []string
+
+	for i, f := range fs {
+		if origins[i] == notSet {
+			required := f.requiredIf != nil && l.holds(f.requiredIf, prefix, fs, origins, v) ||
+				f.requiredUnless != nil && !l.holds(f.requiredUnless, prefix, fs, origins, v)
+			if required {
+				errs = append(errs, fmt.Errorf("%s %s configuration was missing", f.name, configTypeEnvironment))
+			}
+		}
+
+		if f.oneOf != "" {
+			if oneOf[f.oneOf] == nil {
+				groups = append(groups, "oneOf:"+f.oneOf)
+			}
+			oneOf[f.oneOf] = append(oneOf[f.oneOf], i)
+		}
+		if f.exclusive != "" {
+			if exclusive[f.exclusive] == nil {
+				groups = append(groups, "exclusive:"+f.exclusive)
+			}
+			exclusive[f.exclusive] = append(exclusive[f.exclusive], i)
+		}
+	}
+
+	// Groups are checked in the order they're first used, and only
+	// count values that were given explicitly or preserved, rather
+	// than defaults.
+	for _, g := range groups {
+		kind, name, _ := strings.Cut(g, ":")
+		members := oneOf[name]
+		if kind == "exclusive" {
+			members = exclusive[name]
+		}
+
+		var names, set []string
+		for _, i := range members {
+			names = append(names, fs[i].name)
+			if origins[i] == setExplicitly || origins[i] == setPreserved {
+				set = append(set, fs[i].name)
+			}
+		}
+
+		switch {
+		case kind == "oneOf" && len(set) == 0:
+			errs = append(errs, fmt.Errorf("%s %s configuration was missing", joinNames(names, "or"), configTypeEnvironment))
+		case len(set) > 1:
+			errs = append(errs, fmt.Errorf("only one of %s %s configuration may be set", joinNames(set, "and"), configTypeEnvironment))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// joinNames lists names in a sentence, as in "A, B or C".
+func joinNames(names []string, conjunction string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + conjunction + " " + names[len(names)-1]
+}
@@ -0,0 +1,131 @@
+package env
+
+import (
+	"testing"
+)
+
+type conditionsConfig struct {
+	TLSEnabled bool   `env:"TLS_ENABLED"`
+	TLSCert    string `env:"TLS_CERT" requiredIf:"TLS_ENABLED=true"`
+	Password   string `env:"PASSWORD" requiredUnless:"TOKEN"`
+	Mode       string `env:"MODE" default:"local"`
+	Region     string `env:"REGION" requiredIf:"MODE=cloud"`
+}
+
+type groupsConfig struct {
+	Token    string `env:"TOKEN" oneOf:"auth"`
+	Password string `env:"PASSWORD" oneOf:"auth"`
+	KeyFile  string `env:"KEY_FILE" oneOf:"auth"`
+	Verbose  bool   `env:"VERBOSE" exclusive:"log"`
+	Quiet    bool   `env:"QUIET" exclusive:"log" default:"false"`
+}
+
+func TestConditions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source map[string]string
+		exp    conditionsConfig
+		err    string
+	}{
+		{name: "conditions not met", source: map[string]string{"TOKEN": "t"}, exp: conditionsConfig{Mode: "local"}},
+		{name: "requiredIf met", source: map[string]string{"TLS_ENABLED": "1", "TOKEN": "t"}, err: "TLS_CERT environment configuration was missing"},
+		{name: "requiredIf satisfied", source: map[string]string{"TLS_ENABLED": "true", "TLS_CERT": "c", "TOKEN": "t"}, exp: conditionsConfig{TLSEnabled: true, TLSCert: "c", Mode: "local"}},
+		{name: "requiredIf other value", source: map[string]string{"TLS_ENABLED": "false", "TOKEN": "t"}, exp: conditionsConfig{Mode: "local"}},
+		{name: "requiredUnless met", source: map[string]string{}, err: "PASSWORD environment configuration was missing"},
+		{name: "requiredUnless satisfied", source: map[string]string{"PASSWORD": "p"}, exp: conditionsConfig{Password: "p", Mode: "local"}},
+		{name: "requiredIf default", source: map[string]string{"TOKEN": "t", "MODE": "cloud"}, err: "REGION environment configuration was missing"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var config conditionsConfig
+			err := NewLoader(WithSource(mapSource(testCase.source))).Load(&config)
+			if testCase.err != "" {
+				ErrorNotNil(t, err)
+				Equals(t, testCase.err, err.Error())
+				return
+			}
+			ErrorNil(t, err)
+			Equals(t, testCase.exp, config)
+		})
+	}
+}
+
+func TestConditionsAggregated(t *testing.T) {
+	var config conditionsConfig
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"TLS_ENABLED": "true"})),
+		WithErrorAggregation(),
+	)
+
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "TLS_CERT environment configuration was missing\nPASSWORD environment configuration was missing", err.Error())
+}
+
+func TestConditionsPrefix(t *testing.T) {
+	type config struct {
+		Cert string `env:"CERT" requiredIf:"TLS"`
+	}
+
+	l := NewLoader(WithSource(mapSource(map[string]string{"TLS": "1"})), WithPrefix("APP_"))
+	ErrorNil(t, l.Load(&config{}))
+
+	l = NewLoader(WithSource(mapSource(map[string]string{"APP_TLS": "1"})), WithPrefix("APP_"))
+	err := l.Load(&config{})
+	ErrorNotNil(t, err)
+	Equals(t, "CERT environment configuration was missing", err.Error())
+}
+
+func TestConditionsDisallowUnknown(t *testing.T) {
+	type config struct {
+		Cert string `env:"CERT" requiredIf:"TLS"`
+		Key  string `env:"KEY" requiredUnless:"INSECURE=true"`
+	}
+
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"APP_TLS": "1", "APP_CERT": "c", "APP_INSECURE": "true"})),
+		WithPrefix("APP_"),
+		WithDisallowUnknown(),
+	)
+	ErrorNil(t, l.Load(&config{}))
+}
+
+func TestConditionsInvalidTag(t *testing.T) {
+	type config struct {
+		Cert string `env:"CERT" requiredIf:"=true"`
+	}
+
+	err := NewLoader(WithSource(mapSource(map[string]string{}))).Load(&config{})
+	ErrorNotNil(t, err)
+	Equals(t, `invalid requiredIf tag "=true": expected NAME or NAME=value`, err.Error())
+}
+
+func TestGroups(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source map[string]string
+		exp    groupsConfig
+		err    string
+	}{
+		{name: "one of", source: map[string]string{"PASSWORD": "p"}, exp: groupsConfig{Password: "p"}},
+		{name: "none of", source: map[string]string{}, err: "TOKEN, PASSWORD or KEY_FILE environment configuration was missing"},
+		{name: "two of", source: map[string]string{"TOKEN": "t", "KEY_FILE": "k"}, err: "only one of TOKEN and KEY_FILE environment configuration may be set"},
+		{name: "exclusive", source: map[string]string{"TOKEN": "t", "VERBOSE": "true"}, exp: groupsConfig{Token: "t", Verbose: true}},
+		{name: "exclusive both", source: map[string]string{"TOKEN": "t", "VERBOSE": "true", "QUIET": "false"}, err: "only one of VERBOSE and QUIET environment configuration may be set"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var config groupsConfig
+			err := NewLoader(WithSource(mapSource(testCase.source))).Load(&config)
+			if testCase.err != "" {
+				ErrorNotNil(t, err)
+				Equals(t, testCase.err, err.Error())
+				return
+			}
+			ErrorNil(t, err)
+			Equals(t, testCase.exp, config)
+		})
+	}
+}
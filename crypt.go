@@ -0,0 +1,87 @@
+package env
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// EncryptedPrefix marks values encrypted by Encrypt.
+const EncryptedPrefix = "enc:"
+
+var errNoKey = errors.New("no decryption key")
+
+// Encrypt encrypts a value with AES-GCM, using a 16, 24 or 32 byte
+// key, and returns it base64 encoded with EncryptedPrefix, ready to be
+// decrypted by a Loader configured with WithDecryptionKey.
+func Encrypt(key []byte, value string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt.  The prefix is
+// optional.  Errors never include the value.
+func Decrypt(key []byte, value string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", errors.New("value is not valid base64")
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("value is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	b, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WithDecryptionKey sets the key used to decrypt values encrypted by
+// Encrypt, which are recognised by their EncryptedPrefix, along with
+// the values of fields tagged encrypted:"true".  Without a key, values
+// with the prefix are used as they are, other than for encrypted
+// fields, and errors parsing them don't include the value.
+func WithDecryptionKey(key []byte) Option {
+	return func(l *Loader) {
+		l.key = key
+	}
+}
+
+// decrypt returns the plaintext of a field's value if it's encrypted.
+func (l *Loader) decrypt(f field, value string) (string, error) {
+	if !f.encrypted && (l.key == nil || !strings.HasPrefix(value, EncryptedPrefix)) {
+		return value, nil
+	}
+	if l.key == nil {
+		return "", errNoKey
+	}
+	return Decrypt(l.key, value)
+}
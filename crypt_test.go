@@ -0,0 +1,145 @@
+package env
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt(testKey, "hunter2")
+	ErrorNil(t, err)
+	Assert(t, strings.HasPrefix(encrypted, EncryptedPrefix))
+
+	other, err := Encrypt(testKey, "hunter2")
+	ErrorNil(t, err)
+	Assert(t, encrypted != other)
+
+	value, err := Decrypt(testKey, encrypted)
+	ErrorNil(t, err)
+	Equals(t, "hunter2", value)
+
+	value, err = Decrypt(testKey, strings.TrimPrefix(encrypted, EncryptedPrefix))
+	ErrorNil(t, err)
+	Equals(t, "hunter2", value)
+}
+
+func TestDecryptErrors(t *testing.T) {
+	encrypted, err := Encrypt(testKey, "hunter2")
+	ErrorNil(t, err)
+
+	testCases := []struct {
+		name  string
+		key   []byte
+		value string
+		err   string
+	}{
+		{name: "wrong key", key: []byte("fedcba9876543210fedcba9876543210"), value: encrypted, err: "cipher: message authentication failed"},
+		{name: "invalid key", key: []byte("short"), value: encrypted, err: "crypto/aes: invalid key size 5"},
+		{name: "not base64", key: testKey, value: "enc:secret!", err: "value is not valid base64"},
+		{name: "too short", key: testKey, value: "enc:YWJj", err: "value is too short"},
+		{name: "tampered", key: testKey, value: encrypted[:len(encrypted)-4] + "AAAA", err: "cipher: message authentication failed"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Decrypt(testCase.key, testCase.value)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+type cryptConfig struct {
+	Password string `env:"PASSWORD"`
+	Port     int    `env:"PORT"`
+	Token    string `env:"TOKEN" encrypted:"true"`
+	Plain    string `env:"PLAIN"`
+}
+
+func TestDecryptionKey(t *testing.T) {
+	password, _ := Encrypt(testKey, "hunter2")
+	port, _ := Encrypt(testKey, "8080")
+	token, _ := Encrypt(testKey, "t")
+
+	l := NewLoader(
+		WithSource(MapSource{
+			"PASSWORD": password,
+			"PORT":     port,
+			"TOKEN":    strings.TrimPrefix(token, EncryptedPrefix),
+			"PLAIN":    "plain",
+		}),
+		WithDecryptionKey(testKey),
+	)
+
+	var config cryptConfig
+	ErrorNil(t, l.Load(&config))
+	Equals(t, cryptConfig{Password: "hunter2", Port: 8080, Token: "t", Plain: "plain"}, config)
+}
+
+func TestDecryptionErrors(t *testing.T) {
+	encrypted, _ := Encrypt([]byte("fedcba9876543210fedcba9876543210"), "hunter2")
+
+	testCases := []struct {
+		name   string
+		opts   []Option
+		source MapSource
+		err    string
+	}{
+		{name: "wrong key", opts: []Option{WithDecryptionKey(testKey)}, source: MapSource{"PASSWORD": encrypted}, err: `error decrypting "Password": cipher: message authentication failed`},
+		{name: "no key", source: MapSource{"TOKEN": encrypted}, err: `error decrypting "Token": no decryption key`},
+		{name: "unencrypted", opts: []Option{WithDecryptionKey(testKey)}, source: MapSource{"TOKEN": "plain"}, err: `error decrypting "Token": value is not valid base64`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var config cryptConfig
+			err := NewLoader(append(testCase.opts, WithSource(testCase.source))...).Load(&config)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+			Assert(t, !strings.Contains(err.Error(), encrypted[len(EncryptedPrefix):]))
+		})
+	}
+}
+
+func TestEncryptedWithoutKey(t *testing.T) {
+	password, _ := Encrypt(testKey, "hunter2")
+	port, _ := Encrypt(testKey, "8080")
+
+	// Without a key, values are used as they are, as Set has no way to
+	// be given one.
+	var config cryptConfig
+	ErrorNil(t, NewLoader(WithSource(MapSource{"PASSWORD": password})).Load(&config))
+	Equals(t, password, config.Password)
+
+	// But parse errors don't echo the ciphertext.
+	err := NewLoader(WithSource(MapSource{"PORT": port})).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `error setting "Port": value begins with "enc:" but no decryption key is set`, err.Error())
+}
+
+func TestEncryptedFlags(t *testing.T) {
+	port, _ := Encrypt(testKey, "8080")
+
+	var config cryptConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	ErrorNil(t, RegisterFlags(fs, &config))
+	ErrorNil(t, fs.Parse([]string{"-port", port}))
+
+	ErrorNil(t, NewLoader(WithSource(MapSource{}), WithFlags(fs), WithDecryptionKey(testKey)).Load(&config))
+	Equals(t, 8080, config.Port)
+}
+
+func TestInvalidEncryptedTag(t *testing.T) {
+	config := struct {
+		Token string `env:"TOKEN" encrypted:"yes"`
+	}{}
+
+	err := NewLoader(WithSource(MapSource{})).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `invalid encrypted tag "yes": strconv.ParseBool: parsing "yes": invalid syntax`, err.Error())
+}
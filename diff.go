@@ -0,0 +1,87 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Redacted is written in place of the values of secret fields.
+const Redacted = "[REDACTED]"
+
+// Change describes a field whose value differs between two structs,
+// with its values formatted as they would be written in the
+// environment.
+type Change struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// String formats the change for logging, as NAME: old -> new.
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Name, c.Old, c.New)
+}
+
+// Diff compares two values of the same tagged struct type (or
+// pointers to them), returning a Change for each field that differs,
+// in declaration order.  The values of secret fields are replaced with
+// Redacted, so changes can be logged safely.
+func Diff(a, b interface{}) (changes []Change, err error) {
+	return std.Diff(a, b)
+}
+
+// Diff is like the function of the same name, but finds the fields as
+// the Loader reads them, with its tags and names, and names changes
+// with its prefix.
+func (l *Loader) Diff(a, b interface{}) (changes []Change, err error) {
+	va, vb := reflect.Indirect(reflect.ValueOf(a)), reflect.Indirect(reflect.ValueOf(b))
+	if !va.IsValid() || !vb.IsValid() || va.Type() != vb.Type() {
+		return nil, fmt.Errorf("cannot compare %T with %T", a, b)
+	}
+
+	fs, err := l.fields(va.Interface(), l.prefix)
+	if err != nil {
+		return
+	}
+
+	for _, f := range fs {
+		fa, fb := va.FieldByIndex(f.index), vb.FieldByIndex(f.index)
+
+		// Unexported fields can't be set by a Loader anyway.
+		if !fa.CanInterface() || reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+
+		c := Change{Name: f.name, Old: Redacted, New: Redacted}
+		if !f.secret {
+			c.Old, c.New = formatValue(f, fa), formatValue(f, fb)
+		}
+		changes = append(changes, c)
+	}
+
+	return
+}
+
+// formatValue formats the value of a field as it would be written in
+// the environment.
+func formatValue(f field, v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == binaryType:
+		return string(v.Bytes())
+	case f.isList():
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, f.delimiter)
+	}
+	return fmt.Sprint(v.Interface())
+}
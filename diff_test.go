@@ -0,0 +1,117 @@
+package env
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type diffConfig struct {
+	Port     int             `env:"PORT"`
+	Timeout  time.Duration   `env:"TIMEOUT"`
+	Peers    []string        `env:"PEERS" delimiter:";"`
+	Key      []byte          `env:"KEY"`
+	Password string          `env:"PASSWORD" secret:"true"`
+	Token    string          `env:"TOKEN,secret"`
+	Custom   *configDuration `env:"CUSTOM"`
+	Same     string          `env:"SAME"`
+	Untagged map[string]bool // Not compared.
+}
+
+func TestDiff(t *testing.T) {
+	a := diffConfig{
+		Port:     8080,
+		Timeout:  time.Second,
+		Peers:    []string{"a", "b"},
+		Password: "old",
+		Token:    "same",
+		Same:     "same",
+		Untagged: map[string]bool{"a": true},
+	}
+	b := diffConfig{
+		Port:     8081,
+		Timeout:  time.Minute,
+		Peers:    []string{"a", "c"},
+		Key:      []byte("key"),
+		Password: "new",
+		Token:    "same",
+		Custom:   &configDuration{Duration: time.Hour},
+		Same:     "same",
+	}
+
+	changes, err := Diff(a, &b)
+	ErrorNil(t, err)
+	Equals(t, []Change{
+		{Name: "PORT", Old: "8080", New: "8081"},
+		{Name: "TIMEOUT", Old: "1s", New: "1m0s"},
+		{Name: "PEERS", Old: "a;b", New: "a;c"},
+		{Name: "KEY", Old: "", New: "key"},
+		{Name: "PASSWORD", Old: Redacted, New: Redacted},
+		{Name: "CUSTOM", Old: "", New: "{1h0m0s}"},
+	}, changes)
+
+	changes, err = Diff(a, a)
+	ErrorNil(t, err)
+	Equals(t, 0, len(changes))
+}
+
+func TestLoaderDiff(t *testing.T) {
+	type config struct {
+		Host string `cfg:"HOST"`
+		DB   struct {
+			Password string `secret:"true"`
+		}
+	}
+
+	l := NewLoader(WithTags(Tags{Env: "cfg"}), WithAutoNames(), WithPrefix("APP_"))
+
+	a, b := config{Host: "a"}, config{Host: "b"}
+	b.DB.Password = "p"
+	changes, err := l.Diff(a, b)
+	ErrorNil(t, err)
+	Equals(t, []Change{
+		{Name: "APP_HOST", Old: "a", New: "b"},
+		{Name: "APP_DB_PASSWORD", Old: Redacted, New: Redacted},
+	}, changes)
+}
+
+func TestWatcherDiff(t *testing.T) {
+	type config struct {
+		Rate int
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[config](ctx, MapSource{"RATE": "1"}, 0, WithAutoNames())
+	ErrorNil(t, err)
+
+	changes, err := w.Diff(&config{Rate: 1}, &config{Rate: 2})
+	ErrorNil(t, err)
+	Equals(t, []Change{{Name: "RATE", Old: "1", New: "2"}}, changes)
+}
+
+func TestDiffChangeString(t *testing.T) {
+	Equals(t, `PORT: "8080" -> "8081"`, Change{Name: "PORT", Old: "8080", New: "8081"}.String())
+}
+
+func TestDiffErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b interface{}
+		err  string
+	}{
+		{name: "different types", a: diffConfig{}, b: struct{}{}, err: "cannot compare env.diffConfig with struct {}"},
+		{name: "nil", a: nil, b: diffConfig{}, err: "cannot compare <nil> with env.diffConfig"},
+		{name: "nil pointer", a: (*diffConfig)(nil), b: &diffConfig{}, err: "cannot compare *env.diffConfig with *env.diffConfig"},
+		{name: "not a struct", a: 1, b: 2, err: "int is not a struct"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Diff(testCase.a, testCase.b)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package env
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DotEnvExample writes a dotenv-formatted template to w, suitable for
+// use as a .env.example file, describing each of the environment
+// variables read by SetPrefix for the given struct.
+//
+// Each variable is preceded by its "desc" tag as a comment.  Required
+// variables are written uncommented, pre-filled with their default if
+// one exists, while optional variables are commented out.  Fields
+// tagged secret:"true" are always left blank.
+func DotEnvExample(w io.Writer, v interface{}, prefix string) (err error) {
+	fs, err := fields(v, prefix)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	for i, f := range fs {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		if f.desc != "" {
+			for _, line := range strings.Split(f.desc, "\n") {
+				sb.WriteString("# " + line + "\n")
+			}
+		}
+
+		var notes []string
+		if f.required {
+			notes = append(notes, "required")
+		}
+		if f.secret {
+			notes = append(notes, "secret")
+		}
+		if len(notes) > 0 {
+			sb.WriteString("# (" + strings.Join(notes, ", ") + ")\n")
+		}
+
+		var value string
+		if f.hasDefault && !f.secret {
+			value = dotEnvQuote(f.def)
+		}
+
+		if !f.required {
+			sb.WriteString("# ")
+		}
+		sb.WriteString(f.name + "=" + value + "\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return
+}
+
+// dotEnvQuote double-quotes a value if it contains characters that
+// dotenv parsers would otherwise interpret.
+func dotEnvQuote(s string) string {
+	if strings.ContainsAny(s, " \t\r\n#\"'\\$`") {
+		return strconv.Quote(s)
+	}
+	return s
+}
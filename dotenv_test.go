@@ -0,0 +1,51 @@
+package env
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDotEnvExample(t *testing.T) {
+	config := struct {
+		Hosts    []string      `env:"HOSTS" required:"true" desc:"Peers to connect to"`
+		Port     int           `env:"PORT" required:"true" default:"8080"`
+		Timeout  time.Duration `env:"TIMEOUT" default:"1s" desc:"Connection timeout"`
+		Greeting string        `env:"GREETING" default:"hello world"`
+		Password string        `env:"PASSWORD" required:"true" secret:"true" default:"shh" desc:"Database password"`
+		Region   string        `env:"REGION"`
+	}{}
+
+	buf := &bytes.Buffer{}
+	ErrorNil(t, DotEnvExample(buf, &config, "APP_"))
+
+	exp := `# Peers to connect to
+# (required)
+APP_HOSTS=
+
+# (required)
+APP_PORT=8080
+
+# Connection timeout
+# APP_TIMEOUT=1s
+
+# APP_GREETING="hello world"
+
+# Database password
+# (required, secret)
+APP_PASSWORD=
+
+# APP_REGION=
+`
+	Equals(t, exp, buf.String())
+}
+
+func TestDotEnvExampleInvalidSecretTag(t *testing.T) {
+	config := struct {
+		Prop string `env:"PROP" secret:"invalid"`
+	}{}
+
+	err := DotEnvExample(&bytes.Buffer{}, &config, "")
+	ErrorNotNil(t, err)
+	Equals(t, `invalid secret tag "invalid": strconv.ParseBool: parsing "invalid": invalid syntax`, err.Error())
+}
@@ -4,13 +4,28 @@ import (
 	"fmt"
 	"os"
 	"reflect"
-	"strconv"
+	"strings"
 )
 
 type configType string
 
 const (
 	configTypeEnvironment configType = "environment"
+	configTypeFlag        configType = "flag"
+	configTypeVault       configType = "vault"
+)
+
+// origin describes where a field's value was taken from.
+type origin int
+
+const (
+	notSet origin = iota
+	setFromDefault
+	setExplicitly
+
+	// setPreserved values were held by the field before loading, so
+	// they're treated as having been set explicitly by its caller.
+	setPreserved
 )
 
 // Setter is called for any complex struct field with an
@@ -20,6 +35,8 @@ type Setter interface {
 	Set(string) error
 }
 
+var setterType = reflect.TypeOf((*Setter)(nil)).Elem()
+
 // Set sets the fields of a struct from environment config.
 // If a field is unexported or required configuration is not
 // found, an error will be returned.
@@ -31,113 +48,250 @@ func Set(i interface{}) (err error) {
 // with a given prefix. If a field is unexported or required
 // configuration is not found, an error will be returned.
 func SetPrefix(i interface{}, prefix string) (err error) {
-	v := reflect.ValueOf(i)
+	return std.load(i, prefix)
+}
 
-	// Don't try to process a non-pointer value.
-	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return fmt.Errorf("%s is not a pointer", v.Kind())
+// processField will lookup the value named by the field's "env" tag
+// (or failing that, one of its "aliases") and attempt to set it.  If
+// not found, another check for the "required" tag will be performed to
+// decided whether an error needs to be returned.  It returns where the
+// value was taken from, if anywhere.
+func (l *Loader) processField(prefix, profile string, f field, v reflect.Value) (o origin, err error) {
+	// Options in the env tag that couldn't be understood, or that
+	// contradict the field's other tags, are always reported.
+	if f.tagErr != nil {
+		return notSet, f.tagErr
 	}
 
-	v = v.Elem()
-	t := reflect.TypeOf(i).Elem()
-
-	for i := 0; i < t.NumField(); i++ {
-		if err = processField(prefix, t.Field(i), v.Field(i)); err != nil {
+	// If the field is unexported or just not settable, bail at
+	// this point because subsequent operations will fail.
+	if !v.CanSet() {
+		if !l.strict {
 			return
 		}
+		return notSet, fmt.Errorf("field '%s' cannot be set", f.sf.Name)
 	}
 
-	return
-}
-
-// processField will lookup the "env" tag for the property
-// and attempt to set it.  If not found, another check for the
-// "required" tag will be performed to decided whether an error
-// needs to be returned.
-func processField(prefix string, t reflect.StructField, v reflect.Value) (err error) {
-	envTag, ok := t.Tag.Lookup("env")
-	if !ok {
+	// Fields of unsupported types can only be skipped if the loader
+	// isn't strict; otherwise they'll fail when set.
+	if !f.supported && !l.strict {
 		return
 	}
 
-	// If the field is unexported or just not settable, bail at
-	// this point because subsequent operations will fail.
-	if !v.CanSet() {
-		return fmt.Errorf("field '%s' cannot be set", t.Name)
+	// Flags given on the command line take precedence over the
+	// source.
+	key := prefix + f.name
+	if value, ok := l.flagLookup(f.name); ok {
+		l.log("env: %s set from flag", key)
+		return setExplicitly, l.setValue(f, v, value, configTypeFlag)
 	}
 
-	// Lookup the environment variable and if found, set and
-	// return
-	env, ok := os.LookupEnv(prefix + envTag)
-	if ok {
-		return setField(t, v, env)
+	// As are secrets held in Vault.
+	if f.vault != "" && l.vault != nil {
+		value, ok, err := l.vault.Read(f.vault)
+		if err != nil {
+			return notSet, err
+		}
+		if ok {
+			l.log("env: %s set from vault", key)
+			return setExplicitly, l.setValue(f, v, value, configTypeVault)
+		}
 	}
 
-	// If the value isn't found in the environment, look for a
-	// user-defined default value
-	d, ok := t.Tag.Lookup("default")
+	// Lookup the value and if found, set and return
+	found, value, ok, err := l.lookup(prefix, f)
+	if err != nil {
+		return notSet, err
+	}
 	if ok {
-		return setField(t, v, d)
+		if found != key && f.deprecated && l.deprecated != nil {
+			l.deprecated(found, key)
+		}
+		l.log("env: %s set from source", found)
+		return setExplicitly, l.setValue(f, v, value, configTypeEnvironment)
+	}
+
+	// Values that the field held before loading take the place of
+	// defaults, if the Loader preserves them.
+	if l.preserve && !isZero(v) {
+		l.log("env: %s kept existing value", key)
+		return setPreserved, nil
+	}
+
+	// If the value isn't found in the source, look for a
+	// user-defined default value, favouring that of the active profile
+	if def, ok := f.profileDefs[profile]; ok {
+		l.log("env: %s set from %s default", key, profile)
+		return setFromDefault, l.setValue(f, v, def, configTypeEnvironment)
+	}
+	if f.hasDefault {
+		l.log("env: %s set from default", key)
+		return setFromDefault, l.setValue(f, v, f.def, configTypeEnvironment)
+	}
+
+	// An env tag has been provided but a matching value cannot be
+	// found, determine if we should return an error or if a missing
+	// value is ok/expected.
+	l.log("env: %s not set", key)
+	if l.prompter != nil && f.required && f.requiredErr == nil {
+		ok, err := l.prompter.prompt(l, key, f, v)
+		if ok {
+			l.log("env: %s set from prompt", key)
+		}
+		if ok || err != nil {
+			return setExplicitly, err
+		}
+	}
+	return notSet, processMissing(f, configTypeEnvironment)
+}
+
+// lookup looks up the value of a field by its name and then by each of
+// its aliases in turn, returning the key that it was found under.  As
+// it's unclear which to use, names set to different values result in
+// an error.
+func (l *Loader) lookup(prefix string, f field) (key, value string, ok bool, err error) {
+	key = prefix + f.name
+	if value, ok, err = l.lookupKey(key, f); err != nil {
+		return "", "", false, err
 	}
 
-	// An env tag has been provided but a matching environment
-	// variable cannot be found, determine if we should return
-	// an error or if a missing variable is ok/expected.
-	return processMissing(t, envTag, configTypeEnvironment)
+	for _, alias := range f.aliases {
+		aliasKey := prefix + alias
+		aliasValue, found, err := l.lookupKey(aliasKey, f)
+		switch {
+		case err != nil:
+			return "", "", false, err
+		case !found:
+		case !ok:
+			key, value, ok = aliasKey, aliasValue, true
+		case aliasValue != value:
+			return "", "", false, fmt.Errorf("%s and %s are set to different values", key, aliasKey)
+		}
+	}
+
+	return
 }
 
-func setField(t reflect.StructField, v reflect.Value, value string) (err error) {
-	// If field implements the Setter interface, invoke it now and
-	// don't continue attempting to set the primitive values.
-	if _, ok := v.Interface().(Setter); ok {
-		instance := reflect.New(t.Type.Elem())
-		v.Set(instance)
-
-		// Re-assert the type with the newed-up instance and call.
-		setter := v.Interface().(Setter)
-		if err = setter.Set(value); err != nil {
-			return fmt.Errorf("error in custom setter: %v", err)
+// lookupKey looks up the value of a field under a single key.  Lists
+// held by a ListSource are joined with the field's delimiter.  Keys
+// that weren't found because the lookup failed, as reported by an
+// ErrorReporter, result in an error.
+func (l *Loader) lookupKey(key string, f field) (value string, ok bool, err error) {
+	if ls, isList := l.source.(ListSource); isList && f.isList() {
+		if items, found := ls.LookupList(key); found {
+			return strings.Join(items, f.delimiter), true, nil
 		}
+	}
+
+	if value, ok = l.source.Lookup(key); ok {
 		return
 	}
+	return "", false, l.sourceErr()
+}
 
-	// If the given type is a slice, create a slice and return,
-	// otherwise, we're dealing with a primitive type
-	if v.Kind() == reflect.Slice {
-		return setSlice(t, v, value)
+// sourceErr returns the errors reported by the Loader's source since
+// they were last checked, if it's an ErrorReporter.
+func (l *Loader) sourceErr() error {
+	if r, ok := l.source.(ErrorReporter); ok {
+		return r.Err()
 	}
+	return nil
+}
 
-	if err = setBuiltInField(v, value); err != nil {
-		return fmt.Errorf("error setting %q: %v", t.Name, err)
+// setValue decrypts a value, if it's encrypted, and applies the
+// field's file and notEmpty options to it before setting it.
+func (l *Loader) setValue(f field, v reflect.Value, value string, ct configType) error {
+	value, err := l.decrypt(f, value)
+	if err != nil {
+		return fmt.Errorf("error decrypting %q: %v", f.sf.Name, err)
 	}
 
-	return
+	// The value is the path of a file holding the actual value.
+	if f.file {
+		b, err := os.ReadFile(value)
+		if err != nil {
+			return fmt.Errorf("error reading file for %q: %v", f.sf.Name, err)
+		}
+		value = string(b)
+	}
+
+	if f.notEmpty && value == "" {
+		return fmt.Errorf("%s %s configuration was empty", f.name, ct)
+	}
+
+	// Without a key, values that look encrypted are used as they are,
+	// but if they don't parse, the error mustn't echo the ciphertext.
+	err = f.set(v, value)
+	if err != nil && l.key == nil && strings.HasPrefix(value, EncryptedPrefix) {
+		return fmt.Errorf("error setting %q: value begins with %q but no decryption key is set", f.sf.Name, EncryptedPrefix)
+	}
+	return err
+}
+
+// resolveSetter returns the function used to set a field from a
+// string value, based on its type.
+func (l *Loader) resolveSetter(f field) setFunc {
+	// Custom parsers registered with the loader take precedence over
+	// everything else.
+	if parse, ok := l.parsers[f.sf.Type]; ok {
+		return wrapSetter(f, parse)
+	}
+
+	// If field implements the Setter interface, invoke it and don't
+	// attempt to set the primitive values.
+	if isSetter(f.sf.Type) {
+		elem := f.sf.Type.Elem()
+		return func(v reflect.Value, value string) (err error) {
+			instance := reflect.New(elem)
+			v.Set(instance)
+
+			// Re-assert the type with the newed-up instance and call.
+			setter := v.Interface().(Setter)
+			if err = setter.Set(value); err != nil {
+				return fmt.Errorf("error in custom setter: %v", err)
+			}
+			return
+		}
+	}
+
+	// If the given type is a slice, create a slice, otherwise, we're
+	// dealing with a primitive type
+	if f.sf.Type.Kind() == reflect.Slice {
+		if parse, ok := l.parsers[f.sf.Type.Elem()]; ok {
+			return wrapSetter(f, listSetter(f.sf.Type, f.delimiter, parse))
+		}
+		return sliceSetter(f.sf.Type, f.delimiter)
+	}
+
+	return wrapSetter(f, builtInSetter(f.sf.Type))
+}
+
+// wrapSetter adds the name of the field to errors returned by set.
+func wrapSetter(f field, set setFunc) setFunc {
+	return func(v reflect.Value, value string) (err error) {
+		if err = set(v, value); err != nil {
+			return fmt.Errorf("error setting %q: %v", f.sf.Name, err)
+		}
+		return
+	}
 }
 
 // ProcessMissing returns an error if a required tag is found
 // and is set to true.  A different error will be returned if
 // the required tag was present but the value could not be parsed
 // to a Boolean value.
-func processMissing(t reflect.StructField, envTag string, ct configType) (err error) {
-	reqTag, ok := t.Tag.Lookup("required")
-	if !ok {
-		// No required tag was found, this field doesn't expect
-		// an env tag to be provided.
-		return nil
-	}
-
-	var b bool
-	if b, err = strconv.ParseBool(reqTag); err != nil {
-		// The value provided for the required tag is not a valid
-		// Boolean, so inform the user.
-		return fmt.Errorf("invalid required tag %q: %v", reqTag, err)
+func processMissing(f field, ct configType) (err error) {
+	// The value provided for the required tag may not be a valid
+	// Boolean, in which case the user will be informed.
+	if f.requiredErr != nil {
+		return f.requiredErr
 	}
 
-	if b {
+	if f.required {
 		// The value provided for the required tag is valid and is
 		// set to true, so the user needs to know that a required
 		// environment variable could not be found.
-		return fmt.Errorf("%s %s configuration was missing", envTag, ct)
+		return fmt.Errorf("%s %s configuration was missing", f.name, ct)
 	}
 
 	return
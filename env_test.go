@@ -3,6 +3,7 @@ package env
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"strings"
@@ -325,6 +326,23 @@ func TestEnvSetUnexportedProperty(t *testing.T) {
 	Assert(t, strings.Contains(err.Error(), "field 'prop' cannot be set"))
 }
 
+func TestEnvSkipped(t *testing.T) {
+	os.Setenv("PROP", "hello")
+	os.Setenv("-", "skipped")
+	defer os.Unsetenv("-")
+
+	config := struct {
+		Prop    string   `env:"PROP"`
+		Skipped string   `env:"-"`
+		prop    string   `env:"-"`
+		Chan    chan int `env:"-"`
+	}{}
+
+	ErrorNil(t, Set(&config))
+	Equals(t, "hello", config.Prop)
+	Equals(t, "", config.Skipped)
+}
+
 func TestEnvUnsupportedPropertyWithoutTag(t *testing.T) {
 	os.Setenv("PROP", "hello")
 
@@ -664,6 +682,24 @@ func TestEnvCustomTypeStructWithError(t *testing.T) {
 	Assert(t, strings.Contains(err.Error(), errConfigDurationError.Error()))
 }
 
+func TestEnvCustomTypeValueReceiver(t *testing.T) {
+	os.Unsetenv("PROP")
+
+	config := struct {
+		Level configLevel `env:"PROP"`
+	}{}
+
+	// Setters with value receivers can't be allocated, so they're
+	// treated like any other type of their kind.
+	ErrorNil(t, Set(&config))
+	ErrorNil(t, Usage(io.Discard, &config, ""))
+
+	os.Setenv("PROP", "debug")
+	err := Set(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `error setting "Level": struct is not supported`, err.Error())
+}
+
 func TestEnvPrefixed(t *testing.T) {
 	os.Setenv("PROP_PROP", "hello")
 
@@ -688,6 +724,14 @@ type configDurationError struct {
 	Duration time.Duration
 }
 
+type configLevel struct {
+	Level string
+}
+
+func (l configLevel) Set(config string) error {
+	return nil
+}
+
 var errConfigDurationError = errors.New("example error from custom Set code")
 
 func (d *configDurationError) Set(config string) (err error) {
@@ -0,0 +1,240 @@
+// Package envcheck defines an Analyzer that reports mistakes in the
+// struct tags read by the env package, which would otherwise only be
+// caught at runtime.
+//
+// It reports invalid "required" values, unknown or conflicting env tag
+// options, env tags on unexported fields, fields of types the env
+// package can't populate, "default" values (including those of
+// profiles) that won't parse for their field's type, and env names used
+// more than once within a struct.
+package envcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codingconcepts/env/internal/structtag"
+	"github.com/codingconcepts/env/internal/typeinfo"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports mistakes in env struct tags.
+var Analyzer = &analysis.Analyzer{
+	Name:     "envcheck",
+	Doc:      "check struct tags read by github.com/codingconcepts/env",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{(*ast.StructType)(nil)}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		checkStruct(pass, n.(*ast.StructType))
+	})
+
+	return nil, nil
+}
+
+func checkStruct(pass *analysis.Pass, st *ast.StructType) {
+	seen := map[string]string{}
+
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+
+		raw, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tag := reflect.StructTag(raw)
+
+		envTag, ok := tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		envName, opts, err := structtag.ParseEnv(envTag)
+		if envName == "-" {
+			// Excluded from loading.
+			continue
+		}
+		if err != nil {
+			pass.Reportf(f.Tag.Pos(), "%v", err)
+		}
+
+		// Names may be left empty to be derived from the field name.
+		name := fieldName(f)
+		if prev, ok := seen[envName]; ok && envName != "" {
+			pass.Reportf(f.Tag.Pos(), "duplicate env name %q (also used by %s)", envName, prev)
+		} else {
+			seen[envName] = name
+		}
+
+		if !ast.IsExported(name) {
+			pass.Reportf(f.Pos(), "env tag on unexported field %s, which cannot be set", name)
+		}
+
+		if reqTag, ok := tag.Lookup("required"); ok {
+			if _, err := strconv.ParseBool(reqTag); err != nil {
+				pass.Reportf(f.Tag.Pos(), "invalid required tag %q: must be a bool", reqTag)
+			}
+		}
+		if err := opts.Conflict(tag, "required", "secret", "delimiter"); err != nil {
+			pass.Reportf(f.Tag.Pos(), "%v", err)
+		}
+
+		t := pass.TypesInfo.TypeOf(f.Type)
+		if t == nil {
+			continue
+		}
+
+		if !supported(t) {
+			pass.Reportf(f.Type.Pos(), "%s is not supported by env", t)
+			continue
+		}
+
+		// Defaults for fields read from files are paths.
+		if opts.File {
+			continue
+		}
+		if d, ok := tag.Lookup("default"); ok {
+			if err := parse(t, d, delimiter(tag, opts)); err != nil {
+				pass.Reportf(f.Tag.Pos(), "invalid default %q for %s: %v", d, t, err)
+			}
+		}
+
+		// As are the defaults of each profile.
+		defs := structtag.Suffixed(tag, "default.")
+		profiles := make([]string, 0, len(defs))
+		for profile := range defs {
+			profiles = append(profiles, profile)
+		}
+		sort.Strings(profiles)
+		for _, profile := range profiles {
+			if err := parse(t, defs[profile], delimiter(tag, opts)); err != nil {
+				pass.Reportf(f.Tag.Pos(), "invalid default.%s %q for %s: %v", profile, defs[profile], t, err)
+			}
+		}
+	}
+}
+
+func fieldName(f *ast.Field) string {
+	if len(f.Names) > 0 {
+		return f.Names[0].Name
+	}
+
+	// Embedded fields are named after their type.
+	t := f.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+func delimiter(tag reflect.StructTag, opts structtag.Options) string {
+	if opts.HasDelimiter {
+		return opts.Delimiter
+	}
+	if d, ok := tag.Lookup("delimiter"); ok {
+		return d
+	}
+	return ","
+}
+
+// supported mirrors the types handled by the env package's setField,
+// setBuiltInField and makeSlice functions.
+func supported(t types.Type) bool {
+	if typeinfo.IsSetter(t) {
+		return true
+	}
+
+	if _, ok := t.Underlying().(*types.Basic); ok {
+		return typeinfo.BasicKind(t) != ""
+	}
+
+	// Slices are matched on their exact type, so named slice types
+	// aren't supported.
+	s, ok := t.(*types.Slice)
+	if !ok {
+		return false
+	}
+	if typeinfo.IsDuration(s.Elem()) {
+		return true
+	}
+	b, ok := s.Elem().(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch b.Kind() {
+	case types.String, types.Bool,
+		types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64:
+		return true
+	}
+	return false
+}
+
+// parse checks that value will be accepted by the env package when
+// setting a field of type t.
+func parse(t types.Type, value, delimiter string) error {
+	if typeinfo.IsSetter(t) {
+		return nil
+	}
+
+	s, ok := t.(*types.Slice)
+	if !ok {
+		return parseBasic(t, value)
+	}
+
+	// []byte holds the raw value.
+	if b, ok := s.Elem().(*types.Basic); ok && b.Kind() == types.Uint8 {
+		return nil
+	}
+
+	for _, item := range strings.Split(value, delimiter) {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+		if err := parseBasic(s.Elem(), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseBasic(t types.Type, value string) (err error) {
+	switch typeinfo.BasicKind(t) {
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "int":
+		_, err = strconv.ParseInt(value, 0, 64)
+	case "uint":
+		_, err = strconv.ParseUint(value, 0, 64)
+	case "float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "duration":
+		_, err = time.ParseDuration(value)
+	case "string":
+	default:
+		err = fmt.Errorf("%s is not supported", t)
+	}
+	return
+}
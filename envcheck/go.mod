@@ -0,0 +1,15 @@
+module github.com/codingconcepts/env/envcheck
+
+go 1.22.4
+
+require (
+	github.com/codingconcepts/env v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.28.0
+)
+
+require (
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+)
+
+replace github.com/codingconcepts/env => ..
@@ -0,0 +1,75 @@
+package a
+
+import "time"
+
+type duration struct {
+	time.Duration
+}
+
+func (d *duration) Set(s string) (err error) {
+	d.Duration, err = time.ParseDuration(s)
+	return
+}
+
+type myInt int16
+
+// level has a value receiver, so the env package can't allocate it.
+type level struct{ name string }
+
+func (l level) Set(s string) error { return nil }
+
+type myStrings []string
+
+type valid struct {
+	Bool      bool            `env:"BOOL" required:"true" default:"true"`
+	Int       int             `env:"INT" default:"0x10"`
+	Uint      uint8           `env:"UINT" default:"1"`
+	Float     float32         `env:"FLOAT" default:"1.5"`
+	Duration  time.Duration   `env:"DURATION" default:"1m"`
+	Custom    myInt           `env:"CUSTOM" default:"1"`
+	Setter    *duration       `env:"SETTER" default:"anything"`
+	Bytes     []byte          `env:"BYTES" default:"abc"`
+	Strings   []string        `env:"STRINGS" default:"a,b"`
+	Durations []time.Duration `env:"DURATIONS" default:"1s; 2s" delimiter:";"`
+	Untagged  chan int
+}
+
+type invalid struct {
+	Required  string          `env:"REQUIRED" required:"yes"`  // want `invalid required tag "yes": must be a bool`
+	private   string          `env:"PRIVATE"`                  // want `env tag on unexported field private, which cannot be set`
+	Chan      chan int        `env:"CHAN"`                     // want `chan int is not supported by env`
+	Named     myStrings       `env:"NAMED"`                    // want `a.myStrings is not supported by env`
+	Map       map[string]int  `env:"MAP"`                      // want `map\[string\]int is not supported by env`
+	Level     level           `env:"LEVEL"`                    // want `a.level is not supported by env`
+	Int       int             `env:"INT" default:"one"`        // want `invalid default "one" for int: strconv.ParseInt: parsing "one": invalid syntax`
+	Durations []time.Duration `env:"DURATIONS" default:"1s,x"` // want `invalid default "1s,x" for \[\]time.Duration: time: invalid duration "x"`
+	Again     int             `env:"INT"`                      // want `duplicate env name "INT" \(also used by Int\)`
+}
+
+type options struct {
+	Hosts    []string `env:"HOSTS,required,delimiter=;" default:"a;b"`
+	Password string   `env:"PASSWORD,secret,notEmpty"`
+	File     int      `env:"FILE,file" default:"/run/secrets/file"`
+	Derived  int      `env:",required"`
+	Other    int      `env:",required"`
+	Unknown  string   `env:"UNKNOWN,requried"`                   // want `unknown env tag option "requried"`
+	Required string   `env:"REQUIRED,required" required:"false"` // want `env tag option "required" conflicts with required:"false"`
+	Delim    []string `env:"DELIM,delimiter=;" delimiter:","`    // want `env tag option "delimiter=;" conflicts with delimiter:","`
+	Secret   string   `env:"SECRET,secret" secret:"false"`       // want `env tag option "secret" conflicts with secret:"false"`
+	Ints     []int    `env:"INTS,delimiter=;" default:"1,2"`     // want `invalid default "1,2" for \[\]int: strconv.ParseInt: parsing "1,2": invalid syntax`
+}
+
+type excluded struct {
+	Name     string   `env:"NAME"`
+	Skipped  string   `env:"-"`
+	Chan     chan int `env:"-"`
+	private  string   `env:"-"`
+	Required string   `env:"-" required:"yes"`
+}
+
+type profiles struct {
+	Debug   bool          `env:"DEBUG" default:"false" default.dev:"true"`
+	Enabled bool          `env:"ENABLED" default:"false" default.prod:"notabool"` // want `invalid default.prod "notabool" for bool: strconv.ParseBool: parsing "notabool": invalid syntax`
+	Timeout time.Duration `env:"TIMEOUT" default.dev:"x" default.prod:"1s"`       // want `invalid default.dev "x" for time.Duration: time: invalid duration "x"`
+	Path    int           `env:"PATH,file" default.prod:"/run/secrets/path"`
+}
@@ -0,0 +1,327 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/codingconcepts/env/internal/structtag"
+)
+
+// field describes a struct field carrying an env tag, along with the
+// tags that control how a Loader populates it.
+type field struct {
+	sf          reflect.StructField
+	index       []int
+	name        string
+	def         string
+	hasDefault  bool
+	profileDefs map[string]string
+	required    bool
+	requiredErr error
+	secret      bool
+	secretErr   error
+	delimiter   string
+	desc        string
+	notEmpty    bool
+	file        bool
+	aliases     []string
+	deprecated  bool
+	vault       string
+	encrypted   bool
+	tagErr      error
+
+	// requiredIf and requiredUnless make the field required depending
+	// on other values, and oneOf and exclusive name groups of fields
+	// of which exactly and at most one may be set.
+	requiredIf     *condition
+	requiredUnless *condition
+	oneOf          string
+	exclusive      string
+	supported      bool
+
+	// set parses a value into the field, having been resolved from
+	// the field's type ahead of time.
+	set setFunc
+}
+
+// setFunc parses a value into a field.
+type setFunc func(v reflect.Value, value string) error
+
+// plan returns the fields of the given struct type that carry an env
+// tag (or all exported fields, if the Loader derives names), in
+// declaration order.  The result is cached, so callers must not
+// modify it.
+func (l *Loader) plan(t reflect.Type) []field {
+	if p, ok := l.plans.Load(t); ok {
+		return p.([]field)
+	}
+
+	var fs []field
+	l.planStruct(t, nil, "", &fs)
+
+	p, _ := l.plans.LoadOrStore(t, fs)
+	return p.([]field)
+}
+
+// planStruct appends the fields of the given struct type to fs, where
+// index and path locate the struct within the one being loaded.
+func (l *Loader) planStruct(t reflect.Type, index []int, path string, fs *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		envTag, ok := sf.Tag.Lookup(l.tags.Env)
+		if !ok && l.nameFunc == nil {
+			continue
+		}
+
+		// As with encoding/json, fields tagged "-" are never loaded.
+		name, opts, tagErr := structtag.ParseEnv(envTag)
+		if name == "-" {
+			continue
+		}
+
+		// Copy the index, as it's shared by sibling fields.
+		fieldIndex := append(append([]int{}, index...), i)
+
+		// When deriving names, unexported fields without a tag are
+		// skipped (other than embedded structs, whose exported fields
+		// can still be set), and nested structs contribute their name
+		// to the names of their own fields, unless they're embedded.
+		named := name != ""
+		if !ok && !sf.IsExported() && !(sf.Anonymous && l.isNested(sf.Type)) {
+			continue
+		}
+		if !named && l.nameFunc != nil {
+			name = l.nameFunc(sf.Name)
+		}
+		if l.nameFunc != nil && l.isNested(sf.Type) {
+			nested := path
+			if !sf.Anonymous || named {
+				nested = path + name + "_"
+			}
+			l.planStruct(sf.Type, fieldIndex, nested, fs)
+			continue
+		}
+
+		f := field{
+			sf:        sf,
+			index:     fieldIndex,
+			name:      path + name,
+			delimiter: getDelimiter(sf, l.tags.Delimiter, l.delimiter),
+			desc:      sf.Tag.Get(l.tags.Desc),
+			notEmpty:  opts.NotEmpty,
+			file:      opts.File,
+			tagErr:    tagErr,
+		}
+		f.def, f.hasDefault = sf.Tag.Lookup(l.tags.Default)
+		f.profileDefs = profileDefaults(sf.Tag, l.tags.Default)
+		f.required, f.requiredErr = isRequired(sf, l.tags.Required, l.requiredByDefault)
+		f.secret, f.secretErr = isSecret(sf, l.tags.Secret)
+		f.aliases = parseAliases(sf.Tag.Get(l.tags.Aliases))
+		if encrypted, err := isEncrypted(sf, l.tags.Encrypted); err != nil && f.tagErr == nil {
+			f.tagErr = err
+		} else {
+			f.encrypted = encrypted
+		}
+		if c, err := parseCondition(sf, l.tags.RequiredIf); err != nil && f.tagErr == nil {
+			f.tagErr = err
+		} else {
+			f.requiredIf = c
+		}
+		if c, err := parseCondition(sf, l.tags.RequiredUnless); err != nil && f.tagErr == nil {
+			f.tagErr = err
+		} else {
+			f.requiredUnless = c
+		}
+		f.oneOf = sf.Tag.Get(l.tags.OneOf)
+		f.exclusive = sf.Tag.Get(l.tags.Exclusive)
+		if ref, ok := sf.Tag.Lookup(l.tags.Vault); ok {
+			if _, _, err := parseVaultRef(ref); err != nil && f.tagErr == nil {
+				f.tagErr = err
+			}
+			f.vault = ref
+		}
+		if deprecated, err := isDeprecated(sf, l.tags.Deprecated); err != nil && f.tagErr == nil {
+			f.tagErr = err
+		} else {
+			f.deprecated = deprecated
+		}
+
+		// Merge the options in the env tag with the standalone tags,
+		// which must agree if both are present.  Only the first
+		// conflict is reported.
+		if err := opts.Conflict(sf.Tag, l.tags.Required, l.tags.Secret, l.tags.Delimiter); err != nil && f.tagErr == nil {
+			f.tagErr = err
+		}
+		if opts.Required {
+			f.required = true
+		}
+		if opts.Secret {
+			f.secret = true
+		}
+		if opts.HasDelimiter {
+			f.delimiter = opts.Delimiter
+		}
+
+		f.supported = l.supported(sf.Type)
+		f.set = l.resolveSetter(f)
+
+		*fs = append(*fs, f)
+	}
+}
+
+// isNested returns true if fields of the given type are structs whose
+// own fields should be loaded, rather than being set directly.
+func (l *Loader) isNested(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || isSetter(t) || isSetter(reflect.PtrTo(t)) {
+		return false
+	}
+	_, ok := l.parsers[t]
+	return !ok
+}
+
+// fields walks the struct behind v (which may be a struct or a
+// pointer to one) and describes each field carrying an env tag, in
+// declaration order.  The given prefix is applied to each name.
+func fields(v interface{}, prefix string) (out []field, err error) {
+	return std.fields(v, prefix)
+}
+
+// fields is like the function of the same name, but plans the struct
+// with the Loader's tags, names and parsers.
+func (l *Loader) fields(v interface{}, prefix string) (out []field, err error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", t)
+	}
+
+	for _, f := range l.plan(t) {
+		if f.tagErr != nil {
+			return nil, f.tagErr
+		}
+		if f.requiredErr != nil {
+			return nil, f.requiredErr
+		}
+		if f.secretErr != nil {
+			return nil, f.secretErr
+		}
+
+		f.name = prefix + f.name
+		out = append(out, f)
+	}
+
+	return
+}
+
+// isRequired parses the "required" tag of a field, returning def if
+// it's not present.
+func isRequired(t reflect.StructField, tag string, def bool) (b bool, err error) {
+	reqTag, ok := t.Tag.Lookup(tag)
+	if !ok {
+		return def, nil
+	}
+
+	if b, err = strconv.ParseBool(reqTag); err != nil {
+		return false, fmt.Errorf("invalid required tag %q: %v", reqTag, err)
+	}
+	return
+}
+
+// isSecret parses the "secret" tag of a field, returning false if
+// it's not present.  Secret fields hold values such as passwords and
+// keys, which shouldn't be written anywhere they could leak.
+func isSecret(t reflect.StructField, tag string) (b bool, err error) {
+	secretTag, ok := t.Tag.Lookup(tag)
+	if !ok {
+		return false, nil
+	}
+
+	if b, err = strconv.ParseBool(secretTag); err != nil {
+		return false, fmt.Errorf("invalid secret tag %q: %v", secretTag, err)
+	}
+	return
+}
+
+// parseAliases splits an aliases tag into the alternative names it
+// lists, in the order they should be tried.
+func parseAliases(tag string) (aliases []string) {
+	for _, alias := range strings.Split(tag, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return
+}
+
+// isDeprecated parses the "deprecated" tag of a field, returning false
+// if it's not present.  The aliases of deprecated fields are old names
+// that are still read while users migrate to the new one.
+func isDeprecated(t reflect.StructField, tag string) (b bool, err error) {
+	deprecatedTag, ok := t.Tag.Lookup(tag)
+	if !ok {
+		return false, nil
+	}
+
+	if b, err = strconv.ParseBool(deprecatedTag); err != nil {
+		return false, fmt.Errorf("invalid deprecated tag %q: %v", deprecatedTag, err)
+	}
+	return
+}
+
+// isEncrypted parses the "encrypted" tag of a field, returning false
+// if it's not present.  The values of encrypted fields must always be
+// encrypted, whether or not they carry EncryptedPrefix.
+func isEncrypted(t reflect.StructField, tag string) (b bool, err error) {
+	encryptedTag, ok := t.Tag.Lookup(tag)
+	if !ok {
+		return false, nil
+	}
+
+	if b, err = strconv.ParseBool(encryptedTag); err != nil {
+		return false, fmt.Errorf("invalid encrypted tag %q: %v", encryptedTag, err)
+	}
+	return
+}
+
+// isList returns true if the field is populated from a delimited
+// list of values, as opposed to a single value.
+func (f field) isList() bool {
+	return f.sf.Type.Kind() == reflect.Slice && f.sf.Type != binaryType && !isSetter(f.sf.Type)
+}
+
+// isSetter returns true if values of the given type are pointers
+// implementing the Setter interface, which are set by allocating what
+// they point to.  Other types implementing Setter, such as those with
+// a value receiver, are set like any other type of their kind.
+func isSetter(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Implements(setterType)
+}
+
+// supported returns true if the Loader is able to set fields of the
+// given type.
+func (l *Loader) supported(t reflect.Type) bool {
+	if _, ok := l.parsers[t]; ok {
+		return true
+	}
+	if isSetter(t) || t == binaryType || sliceTypes[t] {
+		return true
+	}
+	if t.Kind() == reflect.Slice {
+		_, ok := l.parsers[t.Elem()]
+		return ok
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvTagOptions(t *testing.T) {
+	unsetEnvironment()
+	os.Setenv("HOSTS", "a;b")
+
+	config := struct {
+		Hosts []string `env:"HOSTS,required,delimiter=;"`
+		Port  int      `env:"PORT,required"`
+	}{}
+
+	err := Set(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "PORT environment configuration was missing", err.Error())
+	Equals(t, []string{"a", "b"}, config.Hosts)
+}
+
+func TestEnvTagDelimiterWithComma(t *testing.T) {
+	os.Setenv("PROP", "a,,b,,c")
+
+	config := struct {
+		Prop []string `env:"PROP,required,delimiter=,,"`
+	}{}
+
+	ErrorNil(t, Set(&config))
+	Equals(t, []string{"a", "b", "c"}, config.Prop)
+}
+
+func TestEnvTagNotEmpty(t *testing.T) {
+	os.Setenv("PROP", "")
+
+	config := struct {
+		Prop string `env:"PROP,notEmpty"`
+	}{}
+
+	err := Set(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "PROP environment configuration was empty", err.Error())
+}
+
+func TestEnvTagFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	ErrorNil(t, os.WriteFile(path, []byte("shh"), 0600))
+	os.Setenv("PASSWORD_FILE", path)
+
+	config := struct {
+		Password string `env:"PASSWORD_FILE,file"`
+		Default  string `env:"MISSING_FILE,file" default:"testdata/missing"`
+	}{}
+
+	err := Set(&config)
+	ErrorNotNil(t, err)
+	Assert(t, strings.HasPrefix(err.Error(), `error reading file for "Default": open testdata/missing:`))
+	Equals(t, "shh", config.Password)
+}
+
+func TestEnvTagSecret(t *testing.T) {
+	fs, err := fields(struct {
+		Password string `env:"PASSWORD,secret"`
+	}{}, "")
+
+	ErrorNil(t, err)
+	Assert(t, fs[0].secret)
+}
+
+func TestEnvTagUnknownOption(t *testing.T) {
+	os.Setenv("PROP", "hello")
+
+	config := struct {
+		Prop string `env:"PROP,requried"`
+	}{}
+
+	err := Set(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `unknown env tag option "requried"`, err.Error())
+}
+
+func TestEnvTagConflicts(t *testing.T) {
+	os.Setenv("PROP", "a")
+
+	testCases := []struct {
+		name   string
+		config interface{}
+		exp    string
+	}{
+		{
+			name: "required",
+			config: &struct {
+				Prop string `env:"PROP,required" required:"false"`
+			}{},
+			exp: `env tag option "required" conflicts with required:"false"`,
+		},
+		{
+			name: "secret",
+			config: &struct {
+				Prop string `env:"PROP,secret" secret:"false"`
+			}{},
+			exp: `env tag option "secret" conflicts with secret:"false"`,
+		},
+		{
+			name: "delimiter",
+			config: &struct {
+				Prop []string `env:"PROP,delimiter=;" delimiter:","`
+			}{},
+			exp: `env tag option "delimiter=;" conflicts with delimiter:","`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Set(testCase.config)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.exp, err.Error())
+		})
+	}
+}
+
+func TestEnvTagAgreeingOptions(t *testing.T) {
+	os.Setenv("PROP", "a;b")
+
+	config := struct {
+		Prop []string `env:"PROP,required,delimiter=;" required:"true" delimiter:";"`
+	}{}
+
+	ErrorNil(t, Set(&config))
+	Equals(t, []string{"a", "b"}, config.Prop)
+}
+
+func TestEnvTagOptionsWithAutoNames(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{})),
+		WithAutoNames(),
+	)
+
+	config := struct {
+		MaxConns int `env:",required"`
+	}{}
+
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "MAX_CONNS environment configuration was missing", err.Error())
+}
@@ -0,0 +1,223 @@
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Reloader is implemented by Sources whose values can change after
+// they're created, such as those read from files.
+type Reloader interface {
+	Reload() error
+}
+
+// ListSource is implemented by Sources that can hold lists of values,
+// such as arrays in JSON files.  For slice fields, the items are
+// joined with the field's delimiter before being parsed.
+type ListSource interface {
+	LookupList(key string) ([]string, bool)
+}
+
+// FileSource is a Source whose values are read from files, which are
+// read again on calling Reload.
+type FileSource struct {
+	read func() (fileValues, error)
+
+	mu sync.RWMutex
+	fileValues
+}
+
+// fileValues holds the values read by a FileSource, with lists held
+// separately.
+type fileValues struct {
+	values map[string]string
+	lists  map[string][]string
+}
+
+// NewDotEnvSource returns a FileSource holding the variables assigned
+// in a dotenv file at the given path.
+func NewDotEnvSource(path string) (*FileSource, error) {
+	return newFileSource(func() (fv fileValues, err error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		fv.values, err = parseDotEnv(b)
+		return
+	})
+}
+
+// NewDirSource returns a FileSource holding a value for each file in
+// the given directory, named after the file, in the way that
+// Kubernetes mounts ConfigMaps and Secrets.  Hidden files are skipped,
+// and a trailing newline is trimmed from each value.
+func NewDirSource(dir string) (*FileSource, error) {
+	return newFileSource(func() (fv fileValues, err error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+
+		values := map[string]string{}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+
+			// Stat follows the symlinks Kubernetes mounts files as.
+			path := filepath.Join(dir, e.Name())
+			if fi, err := os.Stat(path); err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fv, err
+			}
+			b = bytes.TrimSuffix(b, []byte("\n"))
+			b = bytes.TrimSuffix(b, []byte("\r"))
+			values[e.Name()] = string(b)
+		}
+		return fileValues{values: values}, nil
+	})
+}
+
+func (fv fileValues) lookup(key string) (string, bool) {
+	if items, ok := fv.lists[key]; ok {
+		return strings.Join(items, ","), true
+	}
+	v, ok := fv.values[key]
+	return v, ok
+}
+
+func (fv fileValues) keys() []string {
+	keys := MapSource(fv.values).Keys()
+	for k := range fv.lists {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func newFileSource(read func() (fileValues, error)) (*FileSource, error) {
+	s := &FileSource{read: read}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Lookup returns the value of key as of the last read.  The items of
+// lists are joined with commas.
+func (s *FileSource) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lookup(key)
+}
+
+// LookupList returns the items of the list held in key as of the last
+// read.
+func (s *FileSource) LookupList(key string) ([]string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items, ok := s.lists[key]
+	return items, ok
+}
+
+// Keys returns the keys held as of the last read.
+func (s *FileSource) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys()
+}
+
+// Reload reads the source's files again.  If they can't be read, the
+// previous values are kept.
+func (s *FileSource) Reload() error {
+	fv, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileValues = fv
+	return nil
+}
+
+// parseDotEnv parses KEY=VALUE assignments, one per line, optionally
+// preceded by "export".  Blank lines and lines beginning with # are
+// ignored, as are comments following unquoted values.  Values in
+// double quotes are unquoted as Go strings, and those in single quotes
+// are taken literally.
+func parseDotEnv(b []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+
+		value, err := dotEnvUnquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
+
+func dotEnvUnquote(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strconv.Unquote(value[:end+1])
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return value[1 : end+1], nil
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// closingQuote returns the index of the double quote closing the one
+// that value begins with, or -1 if there isn't one.
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,126 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	src := `# comment
+
+PORT=8080
+export HOST = localhost
+EMPTY=
+COMMENTED=value # comment
+HASH=a#b
+DOUBLE="a \"quoted\"\nvalue" # comment
+SINGLE='a \n value'
+EQUALS=a=b
+`
+
+	values, err := parseDotEnv([]byte(src))
+	ErrorNil(t, err)
+	Equals(t, map[string]string{
+		"PORT":      "8080",
+		"HOST":      "localhost",
+		"EMPTY":     "",
+		"COMMENTED": "value",
+		"HASH":      "a#b",
+		"DOUBLE":    "a \"quoted\"\nvalue",
+		"SINGLE":    `a \n value`,
+		"EQUALS":    "a=b",
+	}, values)
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{name: "no assignment", src: "A=1\nB", err: "line 2: expected KEY=VALUE"},
+		{name: "no key", src: "=1", err: "line 1: expected KEY=VALUE"},
+		{name: "unterminated double", src: `A="1`, err: "line 1: unterminated quoted value"},
+		{name: "unterminated single", src: `A='1`, err: "line 1: unterminated quoted value"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseDotEnv([]byte(testCase.src))
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestDotEnvSourceRoundTrip(t *testing.T) {
+	config := struct {
+		Name string `env:"NAME" required:"true" default:"a \"b\" #c"`
+	}{}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	f, err := os.Create(path)
+	ErrorNil(t, err)
+	ErrorNil(t, DotEnvExample(f, config, ""))
+	ErrorNil(t, f.Close())
+
+	s, err := NewDotEnvSource(path)
+	ErrorNil(t, err)
+
+	v, ok := s.Lookup("NAME")
+	Assert(t, ok)
+	Equals(t, `a "b" #c`, v)
+}
+
+func TestDotEnvSourceReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	ErrorNil(t, os.WriteFile(path, []byte("A=1\n"), 0600))
+
+	s, err := NewDotEnvSource(path)
+	ErrorNil(t, err)
+	Equals(t, []string{"A"}, s.Keys())
+
+	ErrorNil(t, os.WriteFile(path, []byte("A=2\nB=3\n"), 0600))
+	ErrorNil(t, s.Reload())
+	v, _ := s.Lookup("A")
+	Equals(t, "2", v)
+
+	// Values are kept if the file can't be parsed.
+	ErrorNil(t, os.WriteFile(path, []byte("A\n"), 0600))
+	ErrorNotNil(t, s.Reload())
+	v, _ = s.Lookup("B")
+	Equals(t, "3", v)
+}
+
+func TestDotEnvSourceMissing(t *testing.T) {
+	_, err := NewDotEnvSource(filepath.Join(t.TempDir(), ".env"))
+	ErrorNotNil(t, err)
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	ErrorNil(t, os.WriteFile(filepath.Join(dir, "HOST"), []byte("localhost\n"), 0600))
+	ErrorNil(t, os.WriteFile(filepath.Join(dir, "PASSWORD"), []byte("a\nb\r\n"), 0600))
+	ErrorNil(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("x"), 0600))
+	ErrorNil(t, os.Mkdir(filepath.Join(dir, "SUBDIR"), 0700))
+	ErrorNil(t, os.Symlink(filepath.Join(dir, "HOST"), filepath.Join(dir, "LINK")))
+
+	s, err := NewDirSource(dir)
+	ErrorNil(t, err)
+
+	keys := s.Keys()
+	sort.Strings(keys)
+	Equals(t, []string{"HOST", "LINK", "PASSWORD"}, keys)
+
+	config := struct {
+		Host     string `env:"HOST"`
+		Link     string `env:"LINK"`
+		Password string `env:"PASSWORD"`
+	}{}
+	ErrorNil(t, NewLoader(WithSource(s)).Load(&config))
+	Equals(t, "localhost", config.Host)
+	Equals(t, "localhost", config.Link)
+	Equals(t, "a\nb", config.Password)
+}
@@ -0,0 +1,114 @@
+package env
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+// flagValue is the flag.Value registered for each field by
+// RegisterFlags.  Values are checked with the field's parser when the
+// flag is set, but only stored, to be set by a Loader configured with
+// WithFlags.
+type flagValue struct {
+	l     *Loader
+	f     field
+	value string
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	// Encrypted values can only be checked once they're decrypted by
+	// a Loader.
+	if v.f.encrypted || strings.HasPrefix(value, EncryptedPrefix) {
+		v.value = value
+		return nil
+	}
+
+	if err := v.l.setValue(v.f, reflect.New(v.f.sf.Type).Elem(), value, configTypeFlag); err != nil {
+		return err
+	}
+
+	v.value = value
+	return nil
+}
+
+// IsBoolFlag allows bool fields to be set with -name rather than
+// -name=true.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.f.sf.Type.Kind() == reflect.Bool
+}
+
+// RegisterFlags registers a flag in fs for each field of the struct
+// behind v that carries an env tag, so the same settings can be given
+// on the command line.  Flags are named after the field's "flag" tag,
+// or failing that its env name in lower case with underscores replaced
+// by hyphens, so DB_HOST becomes -db-host.  Fields tagged flag:"-" are
+// skipped.  The usage of each flag is taken from its "desc" tag.
+//
+// Flags don't set fields directly; load the struct with a Loader
+// configured with WithFlags, so that flags given on the command line
+// take precedence over the environment and defaults.
+func RegisterFlags(fs *flag.FlagSet, v interface{}) error {
+	return std.RegisterFlags(fs, v)
+}
+
+// RegisterFlags is like the function of the same name, but names and
+// checks flags in the same way that the Loader reads the fields, with
+// its tags, names and parsers.  Load the struct with a Loader
+// configured with the same options, along with WithFlags.
+func (l *Loader) RegisterFlags(fs *flag.FlagSet, v interface{}) error {
+	tagged, err := l.fields(v, "")
+	if err != nil {
+		return err
+	}
+
+	for _, f := range tagged {
+		if name, ok := flagName(f); ok {
+			fs.Var(&flagValue{l: l, f: f, value: f.def}, name, f.desc)
+		}
+	}
+
+	return nil
+}
+
+// flagName returns the name of the flag for a field, which is taken
+// from its "flag" tag or derived from its env name, and false if it
+// shouldn't have one.
+func flagName(f field) (string, bool) {
+	name, ok := f.sf.Tag.Lookup("flag")
+	if !ok {
+		return strings.ReplaceAll(strings.ToLower(f.name), "_", "-"), true
+	}
+	return name, name != "-"
+}
+
+// WithFlags causes the Loader to set fields from the flags registered
+// in fs by RegisterFlags that were given on the command line, in
+// preference to its source.  Call fs.Parse before loading.
+func WithFlags(fs *flag.FlagSet) Option {
+	return func(l *Loader) {
+		l.flags = fs
+	}
+}
+
+// flagLookup returns the value of the flag registered for the named
+// field, if it was given on the command line.
+func (l *Loader) flagLookup(name string) (value string, ok bool) {
+	if l.flags == nil {
+		return
+	}
+
+	l.flags.Visit(func(fl *flag.Flag) {
+		if v, isEnv := fl.Value.(*flagValue); isEnv && v.f.name == name {
+			value, ok = v.value, true
+		}
+	})
+	return
+}
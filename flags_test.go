@@ -0,0 +1,150 @@
+package env
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type flagsConfig struct {
+	DBHost  string        `env:"DB_HOST" default:"localhost" desc:"Database host"`
+	Port    int           `env:"PORT" flag:"listen-port" default:"8080"`
+	Timeout time.Duration `env:"TIMEOUT"`
+	Verbose bool          `env:"VERBOSE"`
+	Peers   []string      `env:"PEERS"`
+	Hidden  string        `env:"HIDDEN" flag:"-"`
+}
+
+func newFlagSet(t *testing.T, config *flagsConfig, args ...string) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	ErrorNil(t, RegisterFlags(fs, config))
+	ErrorNil(t, fs.Parse(args))
+	return fs
+}
+
+func TestRegisterFlags(t *testing.T) {
+	var config flagsConfig
+	fs := newFlagSet(t, &config)
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, fmt.Sprintf("%s=%q (%s)", f.Name, f.DefValue, f.Usage))
+	})
+	Equals(t, []string{
+		`db-host="localhost" (Database host)`,
+		`listen-port="8080" ()`,
+		`peers="" ()`,
+		`timeout="" ()`,
+		`verbose="" ()`,
+	}, names)
+}
+
+func TestFlagsPrecedence(t *testing.T) {
+	var config flagsConfig
+	fs := newFlagSet(t, &config, "-db-host", "flag-host", "-verbose", "-peers=a,b")
+
+	l := NewLoader(
+		WithSource(MapSource{"DB_HOST": "env-host", "PORT": "9090", "VERBOSE": "false", "HIDDEN": "h"}),
+		WithFlags(fs),
+	)
+	ErrorNil(t, l.Load(&config))
+	Equals(t, flagsConfig{
+		DBHost:  "flag-host",
+		Port:    9090,
+		Verbose: true,
+		Peers:   []string{"a", "b"},
+		Hidden:  "h",
+	}, config)
+
+	// Defaults apply when neither a flag nor the environment is set.
+	config = flagsConfig{}
+	fs = newFlagSet(t, &config, "-listen-port=1")
+	ErrorNil(t, NewLoader(WithSource(MapSource{}), WithFlags(fs)).Load(&config))
+	Equals(t, flagsConfig{DBHost: "localhost", Port: 1}, config)
+}
+
+func TestFlagsPrefix(t *testing.T) {
+	var config flagsConfig
+	fs := newFlagSet(t, &config, "-timeout=1s")
+
+	l := NewLoader(
+		WithSource(MapSource{"APP_TIMEOUT": "1m", "APP_PORT": "1"}),
+		WithPrefix("APP_"),
+		WithFlags(fs),
+	)
+	ErrorNil(t, l.Load(&config))
+	Equals(t, time.Second, config.Timeout)
+	Equals(t, 1, config.Port)
+}
+
+func TestFlagsInvalid(t *testing.T) {
+	var config flagsConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	ErrorNil(t, RegisterFlags(fs, &config))
+
+	err := fs.Parse([]string{"-listen-port=x"})
+	ErrorNotNil(t, err)
+	Equals(t, `invalid value "x" for flag -listen-port: error setting "Port": strconv.ParseInt: parsing "x": invalid syntax`, err.Error())
+}
+
+func TestFlagsNotEmpty(t *testing.T) {
+	config := struct {
+		Name string `env:"NAME,notEmpty"`
+	}{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	ErrorNil(t, RegisterFlags(fs, &config))
+
+	err := fs.Parse([]string{"-name="})
+	ErrorNotNil(t, err)
+	Equals(t, `invalid value "" for flag -name: NAME flag configuration was empty`, err.Error())
+}
+
+func TestLoaderRegisterFlags(t *testing.T) {
+	type addr struct{ port int }
+
+	config := struct {
+		Addr    addr   `cfg:"ADDR"`
+		DBHost  string `cfg:"DB_HOST"`
+		Timeout string
+		DB      struct {
+			Name string
+		}
+	}{}
+
+	// The flags are registered and read with the same options.
+	opts := []Option{
+		WithSource(MapSource{"DB_NAME": "env-name"}),
+		WithTags(Tags{Env: "cfg"}),
+		WithAutoNames(),
+		WithParser(func(s string) (addr, error) {
+			port, err := strconv.Atoi(s)
+			return addr{port}, err
+		}),
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	ErrorNil(t, NewLoader(opts...).RegisterFlags(fs, &config))
+	ErrorNil(t, fs.Parse([]string{"-addr=5", "-db-host=h", "-timeout=1s", "-db-name=flag-name"}))
+
+	ErrorNil(t, NewLoader(append(opts, WithFlags(fs))...).Load(&config))
+	Equals(t, addr{5}, config.Addr)
+	Equals(t, "h", config.DBHost)
+	Equals(t, "1s", config.Timeout)
+	Equals(t, "flag-name", config.DB.Name)
+
+	err := fs.Parse([]string{"-addr=x"})
+	ErrorNotNil(t, err)
+	Equals(t, `invalid value "x" for flag -addr: error setting "Addr": strconv.Atoi: parsing "x": invalid syntax`, err.Error())
+}
+
+func TestRegisterFlagsNotStruct(t *testing.T) {
+	err := RegisterFlags(flag.NewFlagSet("test", flag.ContinueOnError), 1)
+	ErrorNotNil(t, err)
+}
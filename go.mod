@@ -1,3 +1,7 @@
 module github.com/codingconcepts/env
 
 go 1.22.4
+
+require golang.org/x/term v0.27.0
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -0,0 +1,254 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorReporter is implemented by Sources whose lookups can fail, such
+// as those fetching values over a network, and report keys that
+// couldn't be looked up as not found.  A Loader discards the errors
+// left over from earlier lookups when it starts loading, and calls Err
+// after each key that isn't found, failing the field if it returns an
+// error rather than falling back to its default.
+type ErrorReporter interface {
+	// Err returns the errors encountered since it was last called.
+	Err() error
+}
+
+// HTTPConfig configures an HTTPSource.
+type HTTPConfig struct {
+	// URL is that of a JSON object holding every value, which is
+	// flattened in the same way as by NewJSONSource.  If PerKey is
+	// set, the value of each key is instead fetched from the URL with
+	// the key appended as a path segment, where a 404 means the key
+	// isn't set.
+	URL    string
+	PerKey bool
+
+	// Token is sent as a bearer token, if set.
+	Token string
+
+	// Client is used to make requests, defaulting to
+	// http.DefaultClient.
+	Client *http.Client
+
+	// Timeout limits each request, defaulting to ten seconds.
+	Timeout time.Duration
+
+	// Retries is the number of times a request is retried after a
+	// network error or a 429 or 5xx response.  The delay between
+	// attempts starts at RetryDelay (defaulting to 100ms) and doubles
+	// after each retry.
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// HTTPSource is a Source whose values are fetched from a key/value
+// HTTP service.  Responses are cached by ETag, so values that haven't
+// changed aren't downloaded again.
+type HTTPSource struct {
+	ctx context.Context
+	cfg HTTPConfig
+
+	mu sync.RWMutex
+	fileValues
+	etag  string
+	cache map[string]httpValue
+	errs  []error
+}
+
+// httpValue is the cached value of a key fetched by an HTTPSource in
+// PerKey mode.
+type httpValue struct {
+	value string
+	etag  string
+	ok    bool
+}
+
+// NewHTTPSource returns an HTTPSource configured by cfg, which makes
+// requests with the given context.  Unless cfg.PerKey is set, the
+// values are fetched immediately, and again on calling Reload.
+func NewHTTPSource(ctx context.Context, cfg HTTPConfig) (*HTTPSource, error) {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = 100 * time.Millisecond
+	}
+
+	s := &HTTPSource{ctx: ctx, cfg: cfg, cache: map[string]httpValue{}}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload fetches the values again, unless they're fetched per key, in
+// which case every lookup checks for changes.  If they can't be
+// fetched, the previous values are kept.
+func (s *HTTPSource) Reload() error {
+	if s.cfg.PerKey {
+		return nil
+	}
+
+	s.mu.RLock()
+	etag := s.etag
+	s.mu.RUnlock()
+
+	body, etag, status, err := s.get(s.cfg.URL, etag)
+	switch {
+	case err != nil:
+		return err
+	case status == http.StatusNotFound:
+		return fmt.Errorf("GET %s: unexpected status %d %s", s.cfg.URL, status, http.StatusText(status))
+	case status == http.StatusNotModified:
+		return nil
+	}
+
+	fv, err := parseJSON(body)
+	if err != nil {
+		return fmt.Errorf("GET %s: %v", s.cfg.URL, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileValues, s.etag = fv, etag
+	return nil
+}
+
+// Lookup returns the value of key.  In PerKey mode, it's fetched from
+// the service, and errors are reported by Err.
+func (s *HTTPSource) Lookup(key string) (string, bool) {
+	if !s.cfg.PerKey {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		return s.lookup(key)
+	}
+
+	s.mu.RLock()
+	cached := s.cache[key]
+	s.mu.RUnlock()
+
+	u := strings.TrimSuffix(s.cfg.URL, "/") + "/" + url.PathEscape(key)
+	body, etag, status, err := s.get(u, cached.etag)
+	if err != nil {
+		s.mu.Lock()
+		s.errs = append(s.errs, err)
+		s.mu.Unlock()
+		return "", false
+	}
+
+	switch status {
+	case http.StatusOK:
+		cached = httpValue{value: string(body), etag: etag, ok: true}
+	case http.StatusNotFound:
+		cached = httpValue{}
+	}
+
+	s.mu.Lock()
+	s.cache[key] = cached
+	s.mu.Unlock()
+
+	return cached.value, cached.ok
+}
+
+// LookupList returns the items of the list held in key.  Lists are
+// only held when values aren't fetched per key.
+func (s *HTTPSource) LookupList(key string) ([]string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items, ok := s.lists[key]
+	return items, ok
+}
+
+// Keys returns the keys held as of the last fetch, or none if values
+// are fetched per key, as they can't be listed.
+func (s *HTTPSource) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys()
+}
+
+// Err returns the errors encountered by lookups since it was last
+// called.
+func (s *HTTPSource) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := errors.Join(s.errs...)
+	s.errs = nil
+	return err
+}
+
+// get fetches u, retrying as configured, and returns the body and ETag
+// of a 200 response.  304 and 404 responses are returned without a
+// body, and any other status results in an error.  Response bodies
+// are never included in errors, as they may hold secrets.
+func (s *HTTPSource) get(u, etag string) (body []byte, newETag string, status int, err error) {
+	delay := s.cfg.RetryDelay
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		body, newETag, status, retry, err = s.getOnce(u, etag)
+		if !retry || attempt == s.cfg.Retries {
+			return
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return nil, "", 0, s.ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (s *HTTPSource) getOnce(u, etag string) (body []byte, newETag string, status int, retry bool, err error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return
+	}
+	if s.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.Token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		// Only retry if the error isn't down to the context.
+		return nil, "", 0, s.ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch status = resp.StatusCode; {
+	case status == http.StatusOK:
+		if body, err = io.ReadAll(resp.Body); err != nil {
+			return nil, "", 0, true, err
+		}
+		return body, resp.Header.Get("ETag"), status, false, nil
+	case status == http.StatusNotModified || status == http.StatusNotFound:
+		return nil, "", status, false, nil
+	}
+
+	retry = status == http.StatusTooManyRequests || status >= 500
+	return nil, "", status, retry, fmt.Errorf("GET %s: unexpected status %s", u, resp.Status)
+}
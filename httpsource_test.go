@@ -0,0 +1,198 @@
+package env
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type httpConfig struct {
+	Host  string   `env:"DB_HOST" required:"true"`
+	Port  int      `env:"DB_PORT" default:"5432"`
+	Peers []string `env:"PEERS" delimiter:";"`
+}
+
+// kvServer serves a JSON object at / and its values at /KEY, counting
+// the requests made and those answered with a body.
+type kvServer struct {
+	*httptest.Server
+	values   map[string]string
+	json     string
+	requests atomic.Int32
+	bodies   atomic.Int32
+	failures atomic.Int32
+}
+
+func newKVServer(t *testing.T, json string, values map[string]string) *kvServer {
+	s := &kvServer{json: json, values: values}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if s.failures.Load() > 0 {
+			s.failures.Add(-1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body := s.json
+		if key := strings.TrimPrefix(r.URL.Path, "/"); key != "" {
+			v, ok := s.values[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			body = v
+		}
+
+		etag := `"` + body + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		s.bodies.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestHTTPSource(t *testing.T) {
+	s := newKVServer(t, `{"db": {"host": "localhost"}, "peers": ["a", "b"]}`, nil)
+
+	src, err := NewHTTPSource(context.Background(), HTTPConfig{URL: s.URL, Token: "token"})
+	ErrorNil(t, err)
+
+	var config httpConfig
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, httpConfig{Host: "localhost", Port: 5432, Peers: []string{"a", "b"}}, config)
+
+	// Unchanged values aren't downloaded again.
+	ErrorNil(t, src.Reload())
+	Equals(t, int32(2), s.requests.Load())
+	Equals(t, int32(1), s.bodies.Load())
+
+	s.json = `{"db": {"host": "remote"}}`
+	ErrorNil(t, src.Reload())
+	v, ok := src.Lookup("DB_HOST")
+	Assert(t, ok)
+	Equals(t, "remote", v)
+	Equals(t, int32(2), s.bodies.Load())
+}
+
+func TestHTTPSourcePerKey(t *testing.T) {
+	s := newKVServer(t, "", map[string]string{"DB_HOST": "localhost", "PEERS": "a;b"})
+
+	src, err := NewHTTPSource(context.Background(), HTTPConfig{URL: s.URL, Token: "token", PerKey: true})
+	ErrorNil(t, err)
+	Equals(t, int32(0), s.requests.Load())
+
+	var config httpConfig
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, httpConfig{Host: "localhost", Port: 5432, Peers: []string{"a", "b"}}, config)
+	Equals(t, int32(3), s.requests.Load())
+
+	// Values are revalidated on each lookup, but not downloaded again.
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, int32(6), s.requests.Load())
+	Equals(t, int32(2), s.bodies.Load())
+}
+
+func TestHTTPSourceRetries(t *testing.T) {
+	s := newKVServer(t, `{"db": {"host": "localhost"}}`, nil)
+	s.failures.Store(2)
+
+	src, err := NewHTTPSource(context.Background(), HTTPConfig{
+		URL:        s.URL,
+		Token:      "token",
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+	})
+	ErrorNil(t, err)
+	Equals(t, int32(3), s.requests.Load())
+
+	v, _ := src.Lookup("DB_HOST")
+	Equals(t, "localhost", v)
+}
+
+func TestHTTPSourceErrors(t *testing.T) {
+	s := newKVServer(t, `{"db": {"host": "localhost"}}`, nil)
+
+	testCases := []struct {
+		name string
+		cfg  HTTPConfig
+		err  string
+	}{
+		{name: "unauthorized", cfg: HTTPConfig{URL: s.URL, Retries: 1, RetryDelay: time.Millisecond}, err: "GET " + s.URL + ": unexpected status 401 Unauthorized"},
+		{name: "not found", cfg: HTTPConfig{URL: s.URL + "/MISSING", Token: "token"}, err: "GET " + s.URL + "/MISSING: unexpected status 404 Not Found"},
+		{name: "not json", cfg: HTTPConfig{URL: s.URL + "/TEXT", Token: "token"}, err: "GET " + s.URL + "/TEXT: invalid character 'h' looking for beginning of value"},
+	}
+	s.values = map[string]string{"TEXT": "hello"}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewHTTPSource(context.Background(), testCase.cfg)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestHTTPSourcePerKeyErrors(t *testing.T) {
+	s := newKVServer(t, "", map[string]string{"DB_HOST": "localhost"})
+
+	src, err := NewHTTPSource(context.Background(), HTTPConfig{URL: s.URL, PerKey: true})
+	ErrorNil(t, err)
+
+	var config httpConfig
+	err = NewLoader(WithSource(src), WithErrorAggregation()).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "GET "+s.URL+"/DB_HOST: unexpected status 401 Unauthorized\n"+
+		"GET "+s.URL+"/DB_PORT: unexpected status 401 Unauthorized\n"+
+		"GET "+s.URL+"/PEERS: unexpected status 401 Unauthorized", err.Error())
+
+	// Errors are only reported once.
+	ErrorNil(t, src.Err())
+}
+
+func TestHTTPSourcePerKeyErrorsNotAggregated(t *testing.T) {
+	s := newKVServer(t, "", map[string]string{"DB_HOST": "localhost", "DB_PORT": "1"})
+
+	src, err := NewHTTPSource(context.Background(), HTTPConfig{URL: s.URL, Token: "token", PerKey: true})
+	ErrorNil(t, err)
+
+	// Failed lookups fail their field, rather than falling back to its
+	// default or being reported as missing.
+	s.failures.Store(1)
+	var config httpConfig
+	err = NewLoader(WithSource(src)).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "GET "+s.URL+"/DB_HOST: unexpected status 503 Service Unavailable", err.Error())
+
+	s.failures.Store(1)
+	_, ok := src.Lookup("DB_PORT")
+	Assert(t, !ok)
+
+	// Errors from earlier lookups don't fail later loads.
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, httpConfig{Host: "localhost", Port: 1}, config)
+}
+
+func TestHTTPSourceContext(t *testing.T) {
+	s := newKVServer(t, `{}`, nil)
+	s.failures.Store(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewHTTPSource(ctx, HTTPConfig{URL: s.URL, Token: "token", Retries: 5, RetryDelay: time.Hour})
+	ErrorNotNil(t, err)
+	Assert(t, strings.Contains(err.Error(), "context canceled"))
+}
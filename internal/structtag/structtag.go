@@ -0,0 +1,132 @@
+// Package structtag parses the struct tags read by the env package,
+// so that it and its tools agree on them.
+package structtag
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Suffixed returns the values of each of the tags whose names begin
+// with prefix, keyed by the rest of their names.  It parses the tag in
+// the same way as reflect.StructTag.Lookup, as the tags present can't
+// otherwise be listed.
+func Suffixed(tag reflect.StructTag, prefix string) (values map[string]string) {
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to the colon.  A space, a quote or a control character
+		// is a syntax error.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// Scan the quoted string to find the value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok || suffix == "" {
+			continue
+		}
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		if values == nil {
+			values = map[string]string{}
+		}
+		values[suffix] = value
+	}
+
+	return
+}
+
+// Options are the options that can follow the name in an env tag, such
+// as env:"PORT,required".
+type Options struct {
+	Required     bool
+	Secret       bool
+	NotEmpty     bool
+	File         bool
+	Delimiter    string
+	HasDelimiter bool
+}
+
+// ParseEnv splits an env tag into the name and its options.  As
+// delimiters may themselves contain commas, a delimiter option must
+// come last and takes the remainder of the tag.
+func ParseEnv(tag string) (name string, opts Options, err error) {
+	name, rest, more := strings.Cut(tag, ",")
+	for more {
+		if d, ok := strings.CutPrefix(rest, "delimiter="); ok {
+			opts.Delimiter, opts.HasDelimiter = d, true
+			break
+		}
+
+		var opt string
+		opt, rest, more = strings.Cut(rest, ",")
+		switch opt {
+		case "required":
+			opts.Required = true
+		case "secret":
+			opts.Secret = true
+		case "notEmpty":
+			opts.NotEmpty = true
+		case "file":
+			opts.File = true
+		default:
+			return name, opts, fmt.Errorf("unknown env tag option %q", opt)
+		}
+	}
+
+	return
+}
+
+// Conflict returns an error for the first of the options that
+// contradicts the field's standalone tags, which are named required,
+// secret and delimiter, such as env:"PORT,required" alongside
+// required:"false".  Standalone tags that aren't valid are left to be
+// reported when they're parsed.
+func (o Options) Conflict(tag reflect.StructTag, required, secret, delimiter string) error {
+	if b, err := strconv.ParseBool(tag.Get(required)); o.Required && err == nil && !b {
+		return conflictErr("required", required, tag)
+	}
+	if b, err := strconv.ParseBool(tag.Get(secret)); o.Secret && err == nil && !b {
+		return conflictErr("secret", secret, tag)
+	}
+	if d, ok := tag.Lookup(delimiter); ok && o.HasDelimiter && d != o.Delimiter {
+		return conflictErr("delimiter="+o.Delimiter, delimiter, tag)
+	}
+	return nil
+}
+
+func conflictErr(opt, key string, tag reflect.StructTag) error {
+	return fmt.Errorf("env tag option %q conflicts with %s:%q", opt, key, tag.Get(key))
+}
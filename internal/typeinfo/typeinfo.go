@@ -0,0 +1,68 @@
+// Package typeinfo describes how the env package treats types, in
+// terms of go/types, for the tools that check and generate code for
+// its structs.
+package typeinfo
+
+import (
+	"go/types"
+)
+
+// IsSetter mirrors the env package's isSetter, returning true if t is
+// a pointer with a Set(string) error method, as the Setter interface
+// requires.
+func IsSetter(t types.Type) bool {
+	if _, ok := t.(*types.Pointer); !ok {
+		return false
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "Set")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	return types.Identical(sig.Params().At(0).Type(), types.Typ[types.String]) &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// IsDuration returns true if t is time.Duration.
+func IsDuration(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+}
+
+// BasicKind returns the parser the env package uses for values of the
+// given type, which is one of "bool", "string", "int", "uint", "float"
+// and "duration", or an empty string if it isn't a supported basic
+// type.
+func BasicKind(t types.Type) string {
+	if IsDuration(t) {
+		return "duration"
+	}
+
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	switch {
+	case b.Kind() == types.Bool:
+		return "bool"
+	case b.Kind() == types.String:
+		return "string"
+	case b.Info()&types.IsInteger != 0 && b.Info()&types.IsUnsigned != 0 && b.Kind() != types.Uintptr:
+		return "uint"
+	case b.Info()&types.IsInteger != 0 && b.Info()&types.IsUnsigned == 0:
+		return "int"
+	case b.Kind() == types.Float32 || b.Kind() == types.Float64:
+		return "float"
+	}
+	return ""
+}
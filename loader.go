@@ -0,0 +1,370 @@
+package env
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Source provides the values of configuration keys, in the same way
+// that the environment does for Set.
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// SourceFunc adapts a lookup function, such as os.LookupEnv, to a
+// Source.
+type SourceFunc func(key string) (string, bool)
+
+// Lookup calls f(key).
+func (f SourceFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+// Tags names the struct tags read by a Loader.
+type Tags struct {
+	Env        string
+	Default    string
+	Required   string
+	Delimiter  string
+	Desc       string
+	Secret     string
+	Aliases    string
+	Deprecated string
+	Vault      string
+	Encrypted  string
+
+	RequiredIf     string
+	RequiredUnless string
+	OneOf          string
+	Exclusive      string
+}
+
+// DefaultTags are the struct tags read by Set, SetPrefix and Loaders
+// that haven't been configured with WithTags.
+var DefaultTags = Tags{
+	Env:        "env",
+	Default:    "default",
+	Required:   "required",
+	Delimiter:  "delimiter",
+	Desc:       "desc",
+	Secret:     "secret",
+	Aliases:    "aliases",
+	Deprecated: "deprecated",
+	Vault:      "vault",
+	Encrypted:  "encrypted",
+
+	RequiredIf:     "requiredIf",
+	RequiredUnless: "requiredUnless",
+	OneOf:          "oneOf",
+	Exclusive:      "exclusive",
+}
+
+// Loader sets the fields of structs from configuration.  Loaders are
+// configured once with options and are safe for concurrent use, so a
+// single Loader can be shared across packages.
+type Loader struct {
+	source            Source
+	prefix            string
+	tags              Tags
+	delimiter         string
+	strict            bool
+	aggregate         bool
+	parsers           map[reflect.Type]setFunc
+	requiredByDefault bool
+	nameFunc          func(string) string
+	logf              func(format string, args ...interface{})
+	deprecated        func(old, new string)
+	disallowUnknown   bool
+	flags             *flag.FlagSet
+	vault             *Vault
+	key               []byte
+	prompter          *prompter
+	profile           string
+	profileVar        string
+	preserve          bool
+
+	// plans caches the fields of each struct type that's been
+	// loaded, as working them out involves a fair amount of
+	// reflection.
+	plans sync.Map
+}
+
+// Option configures a Loader.
+type Option func(*Loader)
+
+// std is the Loader used by Set and SetPrefix.
+var std = NewLoader()
+
+// NewLoader returns a Loader configured with the given options.
+// Without options, it behaves in the same way as Set.
+func NewLoader(opts ...Option) *Loader {
+	l := &Loader{
+		source:    environment{},
+		tags:      DefaultTags,
+		delimiter: ",",
+		strict:    true,
+		parsers:   map[reflect.Type]setFunc{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// WithSource sets the Source that values are looked up from, in
+// place of the environment.
+func WithSource(s Source) Option {
+	return func(l *Loader) {
+		l.source = s
+	}
+}
+
+// WithPrefix sets the prefix applied to the name of each environment
+// variable.
+func WithPrefix(prefix string) Option {
+	return func(l *Loader) {
+		l.prefix = prefix
+	}
+}
+
+// WithTags sets the names of the struct tags read by the Loader.  Any
+// names left empty fall back to those in DefaultTags.
+func WithTags(tags Tags) Option {
+	return func(l *Loader) {
+		if tags.Env == "" {
+			tags.Env = DefaultTags.Env
+		}
+		if tags.Default == "" {
+			tags.Default = DefaultTags.Default
+		}
+		if tags.Required == "" {
+			tags.Required = DefaultTags.Required
+		}
+		if tags.Delimiter == "" {
+			tags.Delimiter = DefaultTags.Delimiter
+		}
+		if tags.Desc == "" {
+			tags.Desc = DefaultTags.Desc
+		}
+		if tags.Secret == "" {
+			tags.Secret = DefaultTags.Secret
+		}
+		if tags.Aliases == "" {
+			tags.Aliases = DefaultTags.Aliases
+		}
+		if tags.Deprecated == "" {
+			tags.Deprecated = DefaultTags.Deprecated
+		}
+		if tags.Vault == "" {
+			tags.Vault = DefaultTags.Vault
+		}
+		if tags.Encrypted == "" {
+			tags.Encrypted = DefaultTags.Encrypted
+		}
+		if tags.RequiredIf == "" {
+			tags.RequiredIf = DefaultTags.RequiredIf
+		}
+		if tags.RequiredUnless == "" {
+			tags.RequiredUnless = DefaultTags.RequiredUnless
+		}
+		if tags.OneOf == "" {
+			tags.OneOf = DefaultTags.OneOf
+		}
+		if tags.Exclusive == "" {
+			tags.Exclusive = DefaultTags.Exclusive
+		}
+		l.tags = tags
+	}
+}
+
+// WithDelimiter sets the delimiter used to split values for slice
+// fields that don't have a delimiter tag.  It defaults to a comma.
+func WithDelimiter(delimiter string) Option {
+	return func(l *Loader) {
+		l.delimiter = delimiter
+	}
+}
+
+// WithStrict determines whether tagged fields that can't be set,
+// because they're unexported or of an unsupported type, result in an
+// error.  Loaders are strict by default, as Set is; a Loader that
+// isn't strict skips such fields.
+func WithStrict(strict bool) Option {
+	return func(l *Loader) {
+		l.strict = strict
+	}
+}
+
+// WithErrorAggregation causes the Loader to attempt to set every
+// field, returning all of the errors encountered joined together,
+// rather than stopping at the first.
+func WithErrorAggregation() Option {
+	return func(l *Loader) {
+		l.aggregate = true
+	}
+}
+
+// WithParser registers a function used to parse values into fields
+// of type T, and into the items of []T fields.  Parsers take
+// precedence over Setter implementations and built-in types.
+func WithParser[T any](parse func(string) (T, error)) Option {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	return func(l *Loader) {
+		l.parsers[t] = func(v reflect.Value, value string) error {
+			parsed, err := parse(value)
+			if err != nil {
+				return err
+			}
+
+			v.Set(reflect.ValueOf(&parsed).Elem())
+			return nil
+		}
+	}
+}
+
+// WithRequiredByDefault treats fields without a required tag as
+// though they were tagged required:"true".
+func WithRequiredByDefault() Option {
+	return func(l *Loader) {
+		l.requiredByDefault = true
+	}
+}
+
+// WithAutoNames derives the names of fields without an env tag from
+// their Go field names using SnakeCase, so that MaxConnIdleTime is
+// read from MAX_CONN_IDLE_TIME.  Fields tagged env:"-" are skipped, as
+// they always are.
+//
+// The fields of nested structs are also loaded, with the struct's
+// name joined to theirs with an underscore, so that Host in a DB
+// struct is read from DB_HOST.  The fields of embedded structs are
+// loaded as though they belonged to the outer struct.
+func WithAutoNames() Option {
+	return WithNameFunc(SnakeCase)
+}
+
+// WithNameFunc is like WithAutoNames but derives names from Go field
+// names using the given function.
+func WithNameFunc(name func(field string) string) Option {
+	return func(l *Loader) {
+		l.nameFunc = name
+	}
+}
+
+// WithLogger sets a function called to log where each field's value
+// was taken from, with a signature matching log.Printf.  Values are
+// never logged, so secrets won't leak.
+func WithLogger(logf func(format string, args ...interface{})) Option {
+	return func(l *Loader) {
+		l.logf = logf
+	}
+}
+
+// WithDisallowUnknown causes the Loader to return an error for each
+// key in its source that begins with its prefix but isn't read by any
+// field, such as a misspelt variable that would otherwise be silently
+// ignored.  Errors suggest the name that was most likely intended.
+//
+// Without a prefix, every key in the source is checked, so a prefix
+// should be used when loading from the environment.  The source must
+// implement KeyLister.
+func WithDisallowUnknown() Option {
+	return func(l *Loader) {
+		l.disallowUnknown = true
+	}
+}
+
+// WithDeprecationHandler sets a function called when a field tagged
+// deprecated:"true" is set from one of its aliases, with the name of
+// the alias that was used and the name that should replace it.
+func WithDeprecationHandler(handle func(old, new string)) Option {
+	return func(l *Loader) {
+		l.deprecated = handle
+	}
+}
+
+// Load sets the fields of a struct from the Loader's source.  If a
+// field is unexported or required configuration is not found, an
+// error will be returned.
+func (l *Loader) Load(v interface{}) error {
+	return l.load(v, l.prefix)
+}
+
+func (l *Loader) load(i interface{}, prefix string) (err error) {
+	v := reflect.ValueOf(i)
+
+	// Don't try to process a non-pointer value.
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("%s is not a pointer", v.Kind())
+	}
+
+	v = v.Elem()
+	t := reflect.TypeOf(i).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct", t)
+	}
+
+	fs := l.plan(t)
+
+	// Errors left over from previous loads are discarded, and those
+	// from looking up the profile are reported before they could be
+	// mistaken for a field's.
+	l.sourceErr()
+	profile := l.activeProfile()
+
+	var errs []error
+	if err = l.sourceErr(); err != nil {
+		if !l.aggregate {
+			return
+		}
+		errs = append(errs, err)
+	}
+	if l.disallowUnknown {
+		if err = l.unknown(prefix, fs); err != nil {
+			if !l.aggregate {
+				return
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	origins := make([]origin, len(fs))
+	for i, f := range fs {
+		if origins[i], err = l.processField(prefix, profile, f, v.FieldByIndex(f.index)); err == nil {
+			continue
+		}
+		if !l.aggregate {
+			return
+		}
+		errs = append(errs, err)
+	}
+
+	// Conditional requirements depend on the values of other fields.
+	if err = l.checkConditions(prefix, fs, origins, v); err != nil {
+		if !l.aggregate {
+			return
+		}
+		errs = append(errs, err)
+	}
+
+	// Sources whose lookups can fail report any remaining errors, such
+	// as those from checking conditions, separately.
+	if err = l.sourceErr(); err != nil {
+		if !l.aggregate {
+			return
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l *Loader) log(format string, args ...interface{}) {
+	if l.logf != nil {
+		l.logf(format, args...)
+	}
+}
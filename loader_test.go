@@ -0,0 +1,248 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func mapSource(m map[string]string) Source {
+	return MapSource(m)
+}
+
+func TestLoaderSource(t *testing.T) {
+	l := NewLoader(WithSource(mapSource(map[string]string{"PROP": "hello"})))
+
+	config := struct {
+		Prop string `env:"PROP"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "hello", config.Prop)
+}
+
+func TestLoaderPrefix(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"APP_PROP": "hello"})),
+		WithPrefix("APP_"),
+	)
+
+	config := struct {
+		Prop string `env:"PROP"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "hello", config.Prop)
+}
+
+func TestLoaderTags(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PROP": "a;b"})),
+		WithTags(Tags{Env: "cfg", Default: "def"}),
+	)
+
+	config := struct {
+		Prop  []string `cfg:"PROP" delimiter:";"`
+		Other string   `cfg:"OTHER" def:"hello"`
+		Env   string   `env:"PROP"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, []string{"a", "b"}, config.Prop)
+	Equals(t, "hello", config.Other)
+	Equals(t, "", config.Env)
+}
+
+func TestLoaderDelimiter(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PROP": "a b", "OTHER": "a,b"})),
+		WithDelimiter(" "),
+	)
+
+	config := struct {
+		Prop  []string `env:"PROP"`
+		Other []string `env:"OTHER" delimiter:","`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, []string{"a", "b"}, config.Prop)
+	Equals(t, []string{"a", "b"}, config.Other)
+}
+
+func TestLoaderNotStrict(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PROP": "hello"})),
+		WithStrict(false),
+	)
+
+	config := struct {
+		prop  string   `env:"PROP"`
+		Chan  chan int `env:"PROP"`
+		Other string   `env:"PROP"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "", config.prop)
+	Equals(t, "hello", config.Other)
+}
+
+func TestLoaderErrorAggregation(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"INT": "a", "BOOL": "b"})),
+		WithErrorAggregation(),
+	)
+
+	config := struct {
+		Int     int    `env:"INT"`
+		Bool    bool   `env:"BOOL"`
+		Missing string `env:"MISSING" required:"true"`
+	}{}
+
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, strings.Join([]string{
+		`error setting "Int": strconv.ParseInt: parsing "a": invalid syntax`,
+		`error setting "Bool": strconv.ParseBool: parsing "b": invalid syntax`,
+		`MISSING environment configuration was missing`,
+	}, "\n"), err.Error())
+}
+
+func TestLoaderParser(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{
+			"URL":  "https://example.com",
+			"URLS": "http://a, http://b",
+			"BAD":  "%",
+		})),
+		WithParser(url.Parse),
+	)
+
+	config := struct {
+		URL  *url.URL   `env:"URL"`
+		URLs []*url.URL `env:"URLS"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "example.com", config.URL.Host)
+	Equals(t, 2, len(config.URLs))
+	Equals(t, "b", config.URLs[1].Host)
+
+	bad := struct {
+		URL *url.URL `env:"BAD"`
+	}{}
+
+	err := l.Load(&bad)
+	ErrorNotNil(t, err)
+	Assert(t, strings.HasPrefix(err.Error(), `error setting "URL": parse "%"`))
+}
+
+func TestLoaderParserListError(t *testing.T) {
+	type port struct{ n int }
+
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PORTS": "1,bad,3"})),
+		WithParser(func(s string) (port, error) {
+			n, err := strconv.Atoi(s)
+			return port{n}, err
+		}),
+	)
+
+	config := struct {
+		Ports []port `env:"PORTS"`
+	}{}
+
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `error setting "Ports": strconv.Atoi: parsing "bad": invalid syntax`, err.Error())
+	Equals(t, 0, len(config.Ports))
+}
+
+func TestLoaderParserOverridesBuiltIn(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PROP": "yes"})),
+		WithParser(func(s string) (bool, error) {
+			switch s {
+			case "yes":
+				return true, nil
+			case "no":
+				return false, nil
+			}
+			return false, errors.New("expected yes or no")
+		}),
+	)
+
+	config := struct {
+		Prop bool `env:"PROP"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, true, config.Prop)
+}
+
+func TestLoaderRequiredByDefault(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{})),
+		WithRequiredByDefault(),
+	)
+
+	optional := struct {
+		Prop string `env:"PROP" required:"false"`
+		Def  string `env:"DEF" default:"a"`
+	}{}
+	ErrorNil(t, l.Load(&optional))
+
+	required := struct {
+		Prop string `env:"PROP"`
+	}{}
+	err := l.Load(&required)
+	ErrorNotNil(t, err)
+	Equals(t, "PROP environment configuration was missing", err.Error())
+}
+
+func TestLoaderLogger(t *testing.T) {
+	var logs []string
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"APP_PROP": "secret"})),
+		WithPrefix("APP_"),
+		WithLogger(func(format string, args ...interface{}) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		}),
+	)
+
+	config := struct {
+		Prop    string `env:"PROP"`
+		Def     string `env:"DEF" default:"a"`
+		Missing string `env:"MISSING"`
+	}{}
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, []string{
+		"env: APP_PROP set from source",
+		"env: APP_DEF set from default",
+		"env: APP_MISSING not set",
+	}, logs)
+}
+
+func TestLoaderMatchesSetPrefix(t *testing.T) {
+	os.Setenv("APP_PROP", "hello")
+
+	config := struct {
+		Prop string `env:"PROP"`
+	}{}
+
+	ErrorNil(t, NewLoader(WithPrefix("APP_")).Load(&config))
+	Equals(t, "hello", config.Prop)
+}
+
+func TestParseWithOptions(t *testing.T) {
+	c, err := Parse[struct {
+		Prop string `env:"PROP"`
+	}](WithSource(mapSource(map[string]string{"PROP": "hello"})))
+
+	ErrorNil(t, err)
+	Equals(t, "hello", c.Prop)
+}
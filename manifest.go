@@ -0,0 +1,124 @@
+package env
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SecretNamePlaceholder is written in place of the Secret name in
+// the secretKeyRef of secret fields by KubernetesEnv.
+const SecretNamePlaceholder = "<secret-name>"
+
+// KubernetesEnv writes a Kubernetes container env list to w, with an
+// entry for each of the environment variables read by SetPrefix for
+// the given struct.  Values are pre-filled with defaults, while
+// fields tagged secret:"true" reference a key in a Secret with the
+// name SecretNamePlaceholder.  Entries for fields without a default
+// are commented out, as setting them to an empty string would count
+// as setting them.
+func KubernetesEnv(w io.Writer, v interface{}, prefix string) (err error) {
+	fs, err := fields(v, prefix)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("env:\n")
+	for _, f := range fs {
+		if !f.secret && !f.hasDefault {
+			sb.WriteString("  # - name: " + yamlQuote(f.name) + "\n")
+			sb.WriteString("  #   value: \"\"" + manifestComment(f) + "\n")
+			continue
+		}
+
+		sb.WriteString("  - name: " + yamlQuote(f.name) + "\n")
+
+		if f.secret {
+			sb.WriteString("    valueFrom:\n")
+			sb.WriteString("      secretKeyRef:\n")
+			sb.WriteString("        name: " + yamlQuote(SecretNamePlaceholder) + "\n")
+			sb.WriteString("        key: " + yamlQuote(f.name) + "\n")
+			continue
+		}
+
+		sb.WriteString("    value: " + yamlQuote(f.def) + manifestComment(f) + "\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return
+}
+
+// ConfigMapData writes the data block of a Kubernetes ConfigMap to w,
+// with an entry for each of the environment variables read by
+// SetPrefix for the given struct.  Values are pre-filled with
+// defaults, while fields tagged secret:"true" are left out, as they
+// belong in a Secret.  As with KubernetesEnv, entries for fields
+// without a default are commented out.
+func ConfigMapData(w io.Writer, v interface{}, prefix string) (err error) {
+	fs, err := fields(v, prefix)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("data:\n")
+	for _, f := range fs {
+		if f.secret {
+			continue
+		}
+		if !f.hasDefault {
+			sb.WriteString("  # ")
+		} else {
+			sb.WriteString("  ")
+		}
+		sb.WriteString(yamlQuote(f.name) + ": " + yamlQuote(f.def) + manifestComment(f) + "\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return
+}
+
+// ComposeEnvironment writes a docker-compose service environment
+// block to w, with an entry for each of the environment variables
+// read by SetPrefix for the given struct.  Values are pre-filled with
+// defaults, while fields tagged secret:"true" and fields without a
+// default are interpolated from the environment docker-compose is
+// run in.
+func ComposeEnvironment(w io.Writer, v interface{}, prefix string) (err error) {
+	fs, err := fields(v, prefix)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("environment:\n")
+	for _, f := range fs {
+		value := strings.ReplaceAll(f.def, "$", "$$")
+		switch {
+		case f.required && (f.secret || !f.hasDefault):
+			value = "${" + f.name + ":?" + f.name + " is required}"
+		case f.secret || !f.hasDefault:
+			value = "${" + f.name + "}"
+		}
+		sb.WriteString("  " + yamlQuote(f.name) + ": " + yamlQuote(value) + "\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return
+}
+
+// manifestComment returns a trailing YAML comment flagging required
+// fields that have no default to pre-fill.
+func manifestComment(f field) string {
+	if f.required && !f.hasDefault {
+		return " # required"
+	}
+	return ""
+}
+
+// yamlQuote returns s as a double-quoted YAML scalar.  Double-quoted
+// YAML shares its escape sequences with Go, so strconv can be used.
+func yamlQuote(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,64 @@
+package env
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type manifestConfig struct {
+	Hosts    []string      `env:"HOSTS" required:"true"`
+	Port     int           `env:"PORT" default:"8080"`
+	Timeout  time.Duration `env:"TIMEOUT"`
+	Password string        `env:"PASSWORD" required:"true" secret:"true"`
+	Greeting string        `env:"GREETING" default:"\"hi\" $USER"`
+}
+
+func TestKubernetesEnv(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, KubernetesEnv(buf, manifestConfig{}, "APP_"))
+
+	exp := `env:
+  # - name: "APP_HOSTS"
+  #   value: "" # required
+  - name: "APP_PORT"
+    value: "8080"
+  # - name: "APP_TIMEOUT"
+  #   value: ""
+  - name: "APP_PASSWORD"
+    valueFrom:
+      secretKeyRef:
+        name: "<secret-name>"
+        key: "APP_PASSWORD"
+  - name: "APP_GREETING"
+    value: "\"hi\" $USER"
+`
+	Equals(t, exp, buf.String())
+}
+
+func TestConfigMapData(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, ConfigMapData(buf, manifestConfig{}, "APP_"))
+
+	exp := `data:
+  # "APP_HOSTS": "" # required
+  "APP_PORT": "8080"
+  # "APP_TIMEOUT": ""
+  "APP_GREETING": "\"hi\" $USER"
+`
+	Equals(t, exp, buf.String())
+}
+
+func TestComposeEnvironment(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, ComposeEnvironment(buf, manifestConfig{}, "APP_"))
+
+	exp := `environment:
+  "APP_HOSTS": "${APP_HOSTS:?APP_HOSTS is required}"
+  "APP_PORT": "8080"
+  "APP_TIMEOUT": "${APP_TIMEOUT}"
+  "APP_PASSWORD": "${APP_PASSWORD:?APP_PASSWORD is required}"
+  "APP_GREETING": "\"hi\" $$USER"
+`
+	Equals(t, exp, buf.String())
+}
@@ -0,0 +1,32 @@
+package env
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts a Go field name into an upper snake case
+// environment variable name, keeping acronyms together, so that
+// MaxConnIdleTime becomes MAX_CONN_IDLE_TIME and HTTPPort becomes
+// HTTP_PORT.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// Start a new word on a change from lower case or digits
+			// to upper case, or at the last capital of an acronym
+			// that's followed by a word.
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+
+	return sb.String()
+}
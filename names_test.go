@@ -0,0 +1,116 @@
+package env
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnakeCase(t *testing.T) {
+	testCases := []struct {
+		name string
+		exp  string
+	}{
+		{name: "Port", exp: "PORT"},
+		{name: "MaxConnIdleTime", exp: "MAX_CONN_IDLE_TIME"},
+		{name: "HTTPPort", exp: "HTTP_PORT"},
+		{name: "ID", exp: "ID"},
+		{name: "UserID", exp: "USER_ID"},
+		{name: "APIKeyID", exp: "API_KEY_ID"},
+		{name: "S3Bucket", exp: "S3_BUCKET"},
+		{name: "Port2", exp: "PORT2"},
+		{name: "already_snake", exp: "ALREADY_SNAKE"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			Equals(t, testCase.exp, SnakeCase(testCase.name))
+		})
+	}
+}
+
+type autoDB struct {
+	Host    string
+	Port    int `default:"5432"`
+	Timeout time.Duration
+}
+
+type autoEmbedded struct {
+	Region string
+}
+
+type autoConfig struct {
+	autoEmbedded
+	HTTPPort int
+	DB       autoDB
+	Replica  autoDB `env:"READ"`
+	Peers    []string
+	Explicit string `env:"OTHER"`
+	Skipped  string `env:"-"`
+	private  string
+}
+
+func TestLoaderAutoNames(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{
+			"APP_REGION":     "eu",
+			"APP_HTTP_PORT":  "80",
+			"APP_DB_HOST":    "db",
+			"APP_DB_TIMEOUT": "1s",
+			"APP_READ_HOST":  "replica",
+			"APP_PEERS":      "a,b",
+			"APP_OTHER":      "other",
+			"APP_SKIPPED":    "skipped",
+			"APP_PRIVATE":    "private",
+		})),
+		WithPrefix("APP_"),
+		WithAutoNames(),
+	)
+
+	var c autoConfig
+	ErrorNil(t, l.Load(&c))
+
+	Equals(t, "eu", c.Region)
+	Equals(t, 80, c.HTTPPort)
+	Equals(t, autoDB{Host: "db", Port: 5432, Timeout: time.Second}, c.DB)
+	Equals(t, autoDB{Host: "replica", Port: 5432}, c.Replica)
+	Equals(t, []string{"a", "b"}, c.Peers)
+	Equals(t, "other", c.Explicit)
+	Equals(t, "", c.Skipped)
+	Equals(t, "", c.private)
+}
+
+func TestLoaderNameFunc(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"db_host": "db"})),
+		WithNameFunc(strings.ToLower),
+	)
+
+	c := struct {
+		DB struct {
+			Host string
+		}
+	}{}
+
+	// Nested names are joined with an underscore, whatever the name
+	// function.
+	ErrorNil(t, l.Load(&c))
+	Equals(t, "db", c.DB.Host)
+}
+
+func TestLoaderAutoNamesRequired(t *testing.T) {
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{})),
+		WithAutoNames(),
+	)
+
+	c := struct {
+		DB struct {
+			Host string `required:"true"`
+		}
+	}{}
+
+	err := l.Load(&c)
+	ErrorNotNil(t, err)
+	Equals(t, "DB_HOST environment configuration was missing", err.Error())
+}
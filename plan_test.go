@@ -0,0 +1,87 @@
+package env
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type benchConfig struct {
+	Hosts    []string        `env:"HOSTS" required:"true" delimiter:";"`
+	Port     int             `env:"PORT" required:"true"`
+	Debug    bool            `env:"DEBUG" default:"false"`
+	Ratio    float64         `env:"RATIO" default:"0.5"`
+	Timeout  time.Duration   `env:"TIMEOUT" default:"1s"`
+	Backoff  []time.Duration `env:"BACKOFF" default:"1s,2s,4s"`
+	Name     string          `env:"NAME"`
+	Tenant   string          `env:"TENANT" required:"false"`
+	Untagged string
+}
+
+func TestPlanCached(t *testing.T) {
+	typ := reflect.TypeOf(benchConfig{})
+	std.plans.Delete(typ)
+
+	first := std.plan(typ)
+	Equals(t, 8, len(first))
+	Equals(t, &first[0], &std.plan(typ)[0])
+}
+
+func TestPlanConcurrent(t *testing.T) {
+	os.Setenv("PROP", "hello")
+
+	type config struct {
+		Prop string `env:"PROP"`
+	}
+	std.plans.Delete(reflect.TypeOf(config{}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var c config
+			ErrorNil(t, Set(&c))
+			Equals(t, "hello", c.Prop)
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkSetPrefix(b *testing.B) {
+	setBenchEnvironment()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var c benchConfig
+		if err := SetPrefix(&c, "BENCH_"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSetPrefixUncached discards the cached plan before each
+// call, showing the cost that caching saves.
+func BenchmarkSetPrefixUncached(b *testing.B) {
+	setBenchEnvironment()
+	typ := reflect.TypeOf(benchConfig{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		std.plans.Delete(typ)
+
+		var c benchConfig
+		if err := SetPrefix(&c, "BENCH_"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func setBenchEnvironment() {
+	os.Setenv("BENCH_HOSTS", "a;b;c")
+	os.Setenv("BENCH_PORT", "8080")
+	os.Setenv("BENCH_NAME", "bench")
+}
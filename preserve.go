@@ -0,0 +1,35 @@
+package env
+
+import (
+	"reflect"
+)
+
+// WithPreserveValues treats the values that fields already hold as
+// their defaults, so that only values found in the Loader's source (or
+// given as flags, or held in Vault) replace them.  This allows defaults
+// to be set in Go code, before loading:
+//
+//	c := config{Port: 8080}
+//	err := loader.Load(&c)
+//
+// Fields holding zero values fall back to their default tags as usual.
+// Pre-populated fields are never reported as missing.
+func WithPreserveValues() Option {
+	return func(l *Loader) {
+		l.preserve = true
+	}
+}
+
+// isZero returns true if v holds no value worth preserving.  Pointers,
+// including those to Setter implementations, hold a value if they're
+// not nil, even if what they point to is a zero value, and slices only
+// hold one if they have items.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
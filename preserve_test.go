@@ -0,0 +1,108 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type preserveConfig struct {
+	Host     string          `env:"HOST" default:"localhost"`
+	Port     int             `env:"PORT" default:"80"`
+	Hosts    []string        `env:"HOSTS" default:"a,b"`
+	Timeout  *configDuration `env:"TIMEOUT" default:"1s"`
+	Name     string          `env:"NAME" required:"true"`
+	Interval time.Duration   `env:"INTERVAL" default:"1m" default.prod:"1h"`
+}
+
+func TestPreserveValues(t *testing.T) {
+	timeout := configDuration{Duration: 5 * time.Second}
+	config := preserveConfig{
+		Host:     "example.com",
+		Port:     8080,
+		Hosts:    []string{"c"},
+		Timeout:  &timeout,
+		Name:     "app",
+		Interval: time.Second,
+	}
+
+	l := NewLoader(
+		WithSource(mapSource(map[string]string{"PORT": "9090"})),
+		WithProfile("prod"),
+		WithPreserveValues(),
+	)
+	ErrorNil(t, l.Load(&config))
+
+	Equals(t, "example.com", config.Host)
+	Equals(t, 9090, config.Port)
+	Equals(t, []string{"c"}, config.Hosts)
+	Equals(t, &timeout, config.Timeout)
+	Equals(t, "app", config.Name)
+	Equals(t, time.Second, config.Interval)
+}
+
+func TestPreserveValuesZero(t *testing.T) {
+	config := preserveConfig{Hosts: []string{}}
+
+	l := NewLoader(WithSource(mapSource(map[string]string{})), WithPreserveValues())
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "NAME environment configuration was missing", err.Error())
+
+	Equals(t, "localhost", config.Host)
+	Equals(t, 80, config.Port)
+	Equals(t, []string{"a", "b"}, config.Hosts)
+	Equals(t, configDuration{Duration: time.Second}, *config.Timeout)
+}
+
+func TestWithoutPreserveValues(t *testing.T) {
+	config := preserveConfig{Host: "example.com", Port: 8080}
+
+	ErrorNil(t, NewLoader(WithSource(mapSource(map[string]string{"NAME": "app"}))).Load(&config))
+	Equals(t, "localhost", config.Host)
+	Equals(t, 80, config.Port)
+}
+
+func TestIsZero(t *testing.T) {
+	var zeroDuration configDuration
+	testCases := []struct {
+		name string
+		v    interface{}
+		exp  bool
+	}{
+		{name: "zero int", v: 0, exp: true},
+		{name: "int", v: 1, exp: false},
+		{name: "zero string", v: "", exp: true},
+		{name: "nil slice", v: []string(nil), exp: true},
+		{name: "empty slice", v: []string{}, exp: true},
+		{name: "slice", v: []string{""}, exp: false},
+		{name: "nil setter", v: (*configDuration)(nil), exp: true},
+		{name: "setter to zero", v: &zeroDuration, exp: false},
+		{name: "zero duration", v: time.Duration(0), exp: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			Equals(t, testCase.exp, isZero(reflect.ValueOf(testCase.v)))
+		})
+	}
+}
+
+func TestPreserveValuesGroups(t *testing.T) {
+	type config struct {
+		Password     string `env:"PASSWORD" oneOf:"pw"`
+		PasswordFile string `env:"PASSWORD_FILE" oneOf:"pw"`
+	}
+
+	l := NewLoader(WithSource(mapSource(map[string]string{})), WithPreserveValues())
+
+	// Preserved values count toward groups, as they weren't defaults.
+	c := config{Password: "x"}
+	ErrorNil(t, l.Load(&c))
+	Equals(t, "x", c.Password)
+
+	c = config{Password: "x"}
+	err := NewLoader(WithSource(mapSource(map[string]string{"PASSWORD_FILE": "f"})), WithPreserveValues()).Load(&c)
+	ErrorNotNil(t, err)
+	Equals(t, "only one of PASSWORD and PASSWORD_FILE environment configuration may be set", err.Error())
+}
@@ -0,0 +1,43 @@
+package env
+
+import (
+	"reflect"
+
+	"github.com/codingconcepts/env/internal/structtag"
+)
+
+// WithProfile sets the profile whose defaults are used, so that a
+// field tagged default:"10s" default.prod:"2s" defaults to 2s with the
+// prod profile, and to 10s otherwise.
+func WithProfile(profile string) Option {
+	return func(l *Loader) {
+		l.profile = profile
+	}
+}
+
+// WithProfileVar is like WithProfile, but reads the name of the profile
+// from the given key in the Loader's source, such as APP_ENV, when
+// loading.  The key isn't prefixed.  A profile set by WithProfile
+// takes precedence.
+func WithProfileVar(key string) Option {
+	return func(l *Loader) {
+		l.profileVar = key
+	}
+}
+
+// activeProfile returns the name of the profile whose defaults should
+// be used, if any.
+func (l *Loader) activeProfile() string {
+	if l.profile != "" || l.profileVar == "" {
+		return l.profile
+	}
+
+	profile, _ := l.source.Lookup(l.profileVar)
+	return profile
+}
+
+// profileDefaults returns the values of each of the tags named key
+// followed by a dot and the name of a profile, keyed by the profile.
+func profileDefaults(tag reflect.StructTag, key string) map[string]string {
+	return structtag.Suffixed(tag, key+".")
+}
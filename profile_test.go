@@ -0,0 +1,77 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type profileConfig struct {
+	Timeout time.Duration `env:"TIMEOUT" default:"10s" default.prod:"2s" default.dev:"1m"`
+	Level   string        `env:"LEVEL" default.dev:"debug"`
+	Port    int           `env:"PORT" required:"true" default.dev:"8080"`
+}
+
+func TestProfileDefaults(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opts   []Option
+		source MapSource
+		exp    profileConfig
+		err    string
+	}{
+		{name: "none", source: MapSource{"PORT": "1"}, exp: profileConfig{Timeout: 10 * time.Second, Port: 1}},
+		{name: "prod", opts: []Option{WithProfile("prod")}, source: MapSource{"PORT": "1"}, exp: profileConfig{Timeout: 2 * time.Second, Port: 1}},
+		{name: "dev", opts: []Option{WithProfile("dev")}, source: MapSource{}, exp: profileConfig{Timeout: time.Minute, Level: "debug", Port: 8080}},
+		{name: "unknown", opts: []Option{WithProfile("staging")}, source: MapSource{"PORT": "1"}, exp: profileConfig{Timeout: 10 * time.Second, Port: 1}},
+		{name: "set", opts: []Option{WithProfile("dev")}, source: MapSource{"TIMEOUT": "5s", "LEVEL": "info"}, exp: profileConfig{Timeout: 5 * time.Second, Level: "info", Port: 8080}},
+		{name: "var", opts: []Option{WithProfileVar("APP_ENV")}, source: MapSource{"APP_ENV": "dev"}, exp: profileConfig{Timeout: time.Minute, Level: "debug", Port: 8080}},
+		{name: "var unset", opts: []Option{WithProfileVar("APP_ENV")}, source: MapSource{}, err: "PORT environment configuration was missing"},
+		{name: "option over var", opts: []Option{WithProfileVar("APP_ENV"), WithProfile("prod")}, source: MapSource{"APP_ENV": "dev", "PORT": "1"}, exp: profileConfig{Timeout: 2 * time.Second, Port: 1}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var config profileConfig
+			err := NewLoader(append(testCase.opts, WithSource(testCase.source))...).Load(&config)
+			if testCase.err != "" {
+				ErrorNotNil(t, err)
+				Equals(t, testCase.err, err.Error())
+				return
+			}
+			ErrorNil(t, err)
+			Equals(t, testCase.exp, config)
+		})
+	}
+}
+
+func TestProfileDefaultsCustomTag(t *testing.T) {
+	config := struct {
+		Level string `env:"LEVEL" def:"info" def.dev:"debug" default.dev:"ignored"`
+	}{}
+
+	l := NewLoader(WithSource(MapSource{}), WithTags(Tags{Default: "def"}), WithProfile("dev"))
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "debug", config.Level)
+}
+
+func TestParseProfileDefaults(t *testing.T) {
+	testCases := []struct {
+		name string
+		tag  reflect.StructTag
+		exp  map[string]string
+	}{
+		{name: "none", tag: `env:"A" default:"1"`},
+		{name: "profiles", tag: `env:"A" default:"1" default.prod:"2"  default.dev:"a \"b\""`, exp: map[string]string{"prod": "2", "dev": `a "b"`}},
+		{name: "empty profile", tag: `default.:"1"`},
+		{name: "other keys", tag: `defaults.prod:"1" json.prod:"2"`},
+		{name: "malformed", tag: `default.prod:"1" default.dev:2`, exp: map[string]string{"prod": "1"}},
+		{name: "unterminated", tag: `default.prod:"1`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			Equals(t, testCase.exp, profileDefaults(testCase.tag, "default"))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// prompter asks for the values of missing required fields.
+type prompter struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+
+	// fd is the file descriptor of in, if it's a terminal, which
+	// allows the input of secrets to be hidden.  Turning off echo
+	// needs system calls that differ by platform, which is why the
+	// package depends on golang.org/x/term (and, through it, only
+	// golang.org/x/sys) rather than reimplementing them.
+	fd       int
+	terminal bool
+}
+
+// WithPrompt causes the Loader to ask for the values of required
+// fields that aren't set, by writing a prompt to out and reading a line
+// from in, rather than failing.  Answers are parsed in the same way as
+// other values, and the prompt is repeated until a valid answer is
+// given.  If in is a terminal, the input of secret fields is hidden.
+//
+// The prompt gives up and the field is reported as missing when in
+// reaches EOF.
+func WithPrompt(in io.Reader, out io.Writer) Option {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		p.fd, p.terminal = int(f.Fd()), true
+	}
+
+	return func(l *Loader) {
+		l.prompter = p
+	}
+}
+
+// prompt asks for the value of a field until a valid one is given,
+// returning false if no answer is forthcoming.
+func (p *prompter) prompt(l *Loader, key string, f field, v reflect.Value) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if f.desc != "" {
+			fmt.Fprintf(p.out, "%s (%s): ", key, f.desc)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", key)
+		}
+
+		answer, err := p.readLine(f.secret)
+		if err == io.EOF {
+			fmt.Fprintln(p.out)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		// Required fields need an answer.
+		if answer == "" {
+			continue
+		}
+
+		// Parse errors may quote the answer, which mustn't be shown
+		// for secrets.
+		if err = l.setValue(f, v, answer, configTypeEnvironment); err != nil {
+			if f.secret {
+				fmt.Fprintln(p.out, "invalid value")
+			} else {
+				fmt.Fprintf(p.out, "invalid value: %v\n", err)
+			}
+			continue
+		}
+		return true, nil
+	}
+}
+
+// readLine reads a line of input, without echoing it if it's secret
+// and typed at a terminal.
+func (p *prompter) readLine(secret bool) (string, error) {
+	if secret && p.terminal {
+		b, err := term.ReadPassword(p.fd)
+		fmt.Fprintln(p.out)
+		return string(b), err
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
@@ -0,0 +1,96 @@
+package env
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type promptConfig struct {
+	Host     string        `env:"HOST" required:"true" desc:"Database host"`
+	Port     int           `env:"PORT" required:"true"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"1s"`
+	Password string        `env:"PASSWORD,required,secret"`
+	Optional string        `env:"OPTIONAL"`
+}
+
+func TestPrompt(t *testing.T) {
+	in := strings.NewReader("localhost\n\nx\n8080\r\nhunter2")
+	var out bytes.Buffer
+
+	l := NewLoader(
+		WithSource(MapSource{}),
+		WithPrefix("APP_"),
+		WithPrompt(in, &out),
+	)
+
+	var config promptConfig
+	ErrorNil(t, l.Load(&config))
+	Equals(t, promptConfig{Host: "localhost", Port: 8080, Timeout: time.Second, Password: "hunter2"}, config)
+	Equals(t, "APP_HOST (Database host): "+
+		"APP_PORT: "+
+		"APP_PORT: invalid value: error setting \"Port\": strconv.ParseInt: parsing \"x\": invalid syntax\n"+
+		"APP_PORT: "+
+		"APP_PASSWORD: ", out.String())
+}
+
+func TestPromptOnlyMissing(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLoader(
+		WithSource(MapSource{"HOST": "h", "PORT": "1", "PASSWORD": "p"}),
+		WithPrompt(strings.NewReader(""), &out),
+	)
+
+	var config promptConfig
+	ErrorNil(t, l.Load(&config))
+	Equals(t, "", out.String())
+}
+
+func TestPromptEOF(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLoader(
+		WithSource(MapSource{}),
+		WithPrompt(strings.NewReader("localhost\n"), &out),
+	)
+
+	var config promptConfig
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "PORT environment configuration was missing", err.Error())
+	Equals(t, "localhost", config.Host)
+	Equals(t, "HOST (Database host): PORT: \n", out.String())
+}
+
+func TestPromptFile(t *testing.T) {
+	config := struct {
+		Path string `env:"PATH,required,file"`
+	}{}
+
+	var out bytes.Buffer
+	l := NewLoader(
+		WithSource(MapSource{}),
+		WithPrompt(strings.NewReader("/does/not/exist\n"), &out),
+	)
+
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "PATH environment configuration was missing", err.Error())
+	Assert(t, strings.Contains(out.String(), `invalid value: error reading file for "Path"`))
+}
+
+func TestPromptSecretInvalid(t *testing.T) {
+	config := struct {
+		PIN int `env:"PIN,required,secret"`
+	}{}
+
+	var out bytes.Buffer
+	l := NewLoader(
+		WithSource(MapSource{}),
+		WithPrompt(strings.NewReader("12a4\n1234\n"), &out),
+	)
+
+	ErrorNil(t, l.Load(&config))
+	Equals(t, 1234, config.PIN)
+	Equals(t, "PIN: invalid value\nPIN: ", out.String())
+}
@@ -0,0 +1,119 @@
+package env
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns matching the values accepted by each of the built-in
+// parsers.  They avoid syntax that's specific to RE2, so they can be
+// used by any JSON Schema validator.
+const (
+	boolPattern         = `(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)`
+	digits              = `[0-9](_?[0-9])*`
+	hexDigits           = `[0-9a-fA-F](_?[0-9a-fA-F])*`
+	uintPattern         = `(0[xX](_?[0-9a-fA-F])+|0[bB](_?[01])+|0[oO](_?[0-7])+|0(_?[0-7])*|[1-9](_?[0-9])*)`
+	intPattern          = `[+-]?` + uintPattern
+	floatPattern        = `([+-]?(` + decimalFloatPattern + `|` + hexFloatPattern + `|[iI][nN][fF]([iI][nN][iI][tT][yY])?)|[nN][aA][nN])`
+	decimalFloatPattern = `(` + digits + `(\.(` + digits + `)?)?|\.` + digits + `)([eE][+-]?` + digits + `)?`
+	hexFloatPattern     = `0[xX]_?(` + hexDigits + `(\.(` + hexDigits + `)?)?|\.` + hexDigits + `)[pP][+-]?` + digits
+	durationPattern     = `[+-]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)`
+)
+
+type jsonSchema struct {
+	Schema     string                        `json:"$schema"`
+	Type       string                        `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+type jsonSchemaProperty struct {
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	Pattern     string  `json:"pattern,omitempty"`
+	Default     *string `json:"default,omitempty"`
+	WriteOnly   bool    `json:"writeOnly,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema document describing the
+// environment variables read by Set for the given struct, as an
+// object of string values.  Each value has a pattern matching what
+// the field's parser will accept, taking the field's delimiter into
+// account for lists.  Variables that are required and have no
+// default are listed as required.
+func JSONSchema(v interface{}) ([]byte, error) {
+	fs, err := fields(v, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s := jsonSchema{
+		Schema:     jsonSchemaDraft,
+		Type:       "object",
+		Properties: map[string]jsonSchemaProperty{},
+	}
+
+	for _, f := range fs {
+		p := jsonSchemaProperty{
+			Type:        "string",
+			Description: f.desc,
+			WriteOnly:   f.secret,
+		}
+		if f.hasDefault {
+			d := f.def
+			p.Default = &d
+		}
+		if pattern := fieldPattern(f); pattern != "" {
+			p.Pattern = "^" + pattern + "$"
+		}
+		s.Properties[f.name] = p
+
+		if f.required && !f.hasDefault {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// fieldPattern returns an unanchored pattern matching the values
+// accepted for a field, or an empty string if any value is accepted.
+func fieldPattern(f field) string {
+	if isSetter(f.sf.Type) || f.sf.Type == binaryType {
+		return ""
+	}
+
+	if !f.isList() {
+		return kindPattern(f.sf.Type)
+	}
+
+	// Slice values are split on their delimiter and each item is
+	// trimmed of spaces, with empty items being ignored.
+	item := kindPattern(f.sf.Type.Elem())
+	if item == "" {
+		return ""
+	}
+	d := regexp.QuoteMeta(f.delimiter)
+	return ` *(` + item + `)? *(` + d + ` *(` + item + `)? *)*`
+}
+
+func kindPattern(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolPattern
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			return durationPattern
+		}
+		return intPattern
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uintPattern
+	case reflect.Float32, reflect.Float64:
+		return floatPattern
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestJSONSchema(t *testing.T) {
+	config := struct {
+		Hosts    []string      `env:"HOSTS" required:"true" desc:"Peers to connect to"`
+		Port     int           `env:"PORT" required:"true" default:"8080"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		Password string        `env:"PASSWORD" secret:"true"`
+	}{}
+
+	b, err := JSONSchema(&config)
+	ErrorNil(t, err)
+
+	exp := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "HOSTS": {
+      "type": "string",
+      "description": "Peers to connect to"
+    },
+    "PASSWORD": {
+      "type": "string",
+      "writeOnly": true
+    },
+    "PORT": {
+      "type": "string",
+      "pattern": "^[+-]?` + jsonEscape(uintPattern) + `$",
+      "default": "8080"
+    },
+    "TIMEOUT": {
+      "type": "string",
+      "pattern": "^` + jsonEscape(durationPattern) + `$"
+    }
+  },
+  "required": [
+    "HOSTS"
+  ]
+}`
+	Equals(t, exp, string(b))
+}
+
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
+
+func TestJSONSchemaPatternsMatchParsers(t *testing.T) {
+	values := []string{
+		"", " ", "0", "1", "-1", "+1", "007", "08", "0x1F", "0X_1f", "0b101", "0o17", "1_000", "_1", "1_",
+		"1.5", ".5", "5.", "1_0.5", "1_.5", "1e10", "1e1_0", "1E-3", "-1.5e+3", "0x1p-2", "inf", "-Inf", "+INFINITY", "NaN", "nan", "-nan",
+		"true", "True", "TRUE", "t", "T", "false", "F", "yes", "tRUE",
+		"1s", "1h2m3s", "1.5h", "-1m", "300ms", "2us", "2µs", "0", "1", "1x", "h",
+		" 1 ", "1 2",
+	}
+
+	config := struct {
+		Bool     bool          `env:"VALUE"`
+		Int      int64         `env:"VALUE"`
+		Uint     uint64        `env:"VALUE"`
+		Float    float64       `env:"VALUE"`
+		Duration time.Duration `env:"VALUE"`
+	}{}
+
+	fs, err := fields(&config, "")
+	ErrorNil(t, err)
+
+	for _, f := range fs {
+		re := regexp.MustCompile("^" + fieldPattern(f) + "$")
+
+		for _, value := range values {
+			t.Run(fmt.Sprintf("%s %q", f.sf.Name, value), func(t *testing.T) {
+				t.Setenv("VALUE", value)
+
+				v := reflect.New(reflect.StructOf([]reflect.StructField{
+					{Name: f.sf.Name, Type: f.sf.Type, Tag: f.sf.Tag},
+				}))
+				Equals(t, Set(v.Interface()) == nil, re.MatchString(value))
+			})
+		}
+	}
+}
+
+func TestJSONSchemaListPatterns(t *testing.T) {
+	config := struct {
+		Ints      []int           `env:"INTS"`
+		Durations []time.Duration `env:"DURATIONS" delimiter:";"`
+		Strings   []string        `env:"STRINGS"`
+	}{}
+
+	fs, err := fields(&config, "")
+	ErrorNil(t, err)
+
+	ints := regexp.MustCompile("^" + fieldPattern(fs[0]) + "$")
+	Assert(t, ints.MatchString(""))
+	Assert(t, ints.MatchString("1, 2, 3"))
+	Assert(t, ints.MatchString("1,,2"))
+	Assert(t, !ints.MatchString("1;2"))
+	Assert(t, !ints.MatchString("1,a"))
+
+	durations := regexp.MustCompile("^" + fieldPattern(fs[1]) + "$")
+	Assert(t, durations.MatchString("1s; 2m;3h"))
+	Assert(t, !durations.MatchString("1s,2m"))
+
+	Equals(t, "", fieldPattern(fs[2]))
+}
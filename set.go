@@ -9,25 +9,50 @@ import (
 )
 
 var (
-	binaryType = reflect.TypeOf([]uint8{})
+	binaryType   = reflect.TypeOf([]uint8{})
+	durationType = reflect.TypeOf((*time.Duration)(nil)).Elem()
+
+	// sliceTypes are the slice types that can be populated from a
+	// delimited list of values.
+	sliceTypes = map[reflect.Type]bool{
+		reflect.TypeOf([]string{}):        true,
+		reflect.TypeOf([]bool{}):          true,
+		reflect.TypeOf([]int{}):           true,
+		reflect.TypeOf([]int8{}):          true,
+		reflect.TypeOf([]int16{}):         true,
+		reflect.TypeOf([]int32{}):         true,
+		reflect.TypeOf([]int64{}):         true,
+		reflect.TypeOf([]uint{}):          true,
+		reflect.TypeOf([]uint16{}):        true,
+		reflect.TypeOf([]uint32{}):        true,
+		reflect.TypeOf([]uint64{}):        true,
+		reflect.TypeOf([]float32{}):       true,
+		reflect.TypeOf([]float64{}):       true,
+		reflect.TypeOf([]time.Duration{}): true,
+	}
 )
 
-// setField determines a field's type and parses the given value
-// accordingly.  An error will be returned if the field is unexported.
-func setBuiltInField(fieldValue reflect.Value, value string) (err error) {
-	switch fieldValue.Kind() {
+// builtInSetter returns the function used to parse values into a
+// field of the given type.
+func builtInSetter(t reflect.Type) setFunc {
+	switch t.Kind() {
 	case reflect.Bool:
-		return setBool(fieldValue, value)
+		return setBool
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return setInt(fieldValue, value)
+		if t == durationType {
+			return setDuration
+		}
+		return setInt
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return setUint(fieldValue, value)
+		return setUint
 	case reflect.Float32, reflect.Float64:
-		return setFloat(fieldValue, value)
+		return setFloat
 	case reflect.String:
-		return setString(fieldValue, value)
+		return setString
 	default:
-		return fmt.Errorf("%s is not supported", fieldValue.Kind())
+		return func(fieldValue reflect.Value, value string) error {
+			return fmt.Errorf("%s is not supported", fieldValue.Kind())
+		}
 	}
 }
 
@@ -42,10 +67,6 @@ func setBool(fieldValue reflect.Value, value string) (err error) {
 }
 
 func setInt(fieldValue reflect.Value, value string) (err error) {
-	if fieldValue.Type() == reflect.TypeOf((*time.Duration)(nil)).Elem() {
-		return setDuration(fieldValue, value)
-	}
-
 	var i int64
 	if i, err = strconv.ParseInt(value, 0, 64); err != nil {
 		return err
@@ -90,72 +111,52 @@ func setString(fieldValue reflect.Value, value string) (err error) {
 	return
 }
 
-func setSlice(t reflect.StructField, v reflect.Value, value string) (err error) {
+// sliceSetter returns the function used to parse delimited values
+// into a slice of the given type.
+func sliceSetter(t reflect.Type, delimiter string) setFunc {
 	// []uint8 and []byte are special cases, as they can be used to store
 	// binary data, which we'll favour over storing comma-separated uint8s.
-	if t.Type == binaryType {
-		v.SetBytes([]byte(value))
-		return
-	}
-
-	// Allow the user to provide their own delimiter, falling back to a
-	// comma if one isn't provided.
-	delimiter := getDelimiter(t)
-	rawValues := split(value, delimiter)
-	if len(rawValues) == 0 {
-		return
+	if t == binaryType {
+		return func(v reflect.Value, value string) (err error) {
+			v.SetBytes([]byte(value))
+			return
+		}
 	}
 
-	sliceValue, err := makeSlice(v, len(rawValues))
-	if err != nil {
-		return
+	if !sliceTypes[t] {
+		return func(v reflect.Value, value string) error {
+			return fmt.Errorf("%v is not supported", t)
+		}
 	}
 
-	populateSlice(sliceValue, rawValues)
-	v.Set(sliceValue)
-
-	return
+	// Items of built-in types that can't be parsed are left as zero
+	// values.
+	setItem := builtInSetter(t.Elem())
+	return listSetter(t, delimiter, func(v reflect.Value, value string) error {
+		setItem(v, value)
+		return nil
+	})
 }
 
-func makeSlice(v reflect.Value, n int) (slice reflect.Value, err error) {
-	switch v.Type() {
-	case reflect.TypeOf([]string{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]string{}), n, n)
-	case reflect.TypeOf([]bool{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]bool{}), n, n)
-	case reflect.TypeOf([]int{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]int{}), n, n)
-	case reflect.TypeOf([]int8{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]int8{}), n, n)
-	case reflect.TypeOf([]int16{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]int16{}), n, n)
-	case reflect.TypeOf([]int32{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]int32{}), n, n)
-	case reflect.TypeOf([]int64{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]int64{}), n, n)
-	case reflect.TypeOf([]uint{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]uint{}), n, n)
-	case reflect.TypeOf([]uint16{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]uint16{}), n, n)
-	case reflect.TypeOf([]uint32{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]uint32{}), n, n)
-	case reflect.TypeOf([]uint64{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]uint64{}), n, n)
-	case reflect.TypeOf([]float32{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]float32{}), n, n)
-	case reflect.TypeOf([]float64{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]float64{}), n, n)
-	case reflect.TypeOf([]time.Duration{}):
-		slice = reflect.MakeSlice(reflect.TypeOf([]time.Duration{}), n, n)
-	default:
-		err = fmt.Errorf("%v is not supported", v.Type())
-	}
-	return
-}
+// listSetter returns the function used to parse delimited values into
+// a slice of the given type, using setItem to parse each item.  The
+// first error returned by setItem is returned.
+func listSetter(t reflect.Type, delimiter string, setItem setFunc) setFunc {
+	return func(v reflect.Value, value string) (err error) {
+		rawValues := split(value, delimiter)
+		if len(rawValues) == 0 {
+			return
+		}
 
-func populateSlice(sliceValue reflect.Value, rawItems []string) {
-	for i, item := range rawItems {
-		setBuiltInField(sliceValue.Index(i), item)
+		sliceValue := reflect.MakeSlice(t, len(rawValues), len(rawValues))
+		for i, item := range rawValues {
+			if err = setItem(sliceValue.Index(i), item); err != nil {
+				return
+			}
+		}
+		v.Set(sliceValue)
+
+		return
 	}
 }
 
@@ -173,9 +174,9 @@ func split(value string, delimeter string) []string {
 	return out
 }
 
-func getDelimiter(t reflect.StructField) string {
-	if d, ok := t.Tag.Lookup("delimiter"); ok {
+func getDelimiter(t reflect.StructField, tag, def string) string {
+	if d, ok := t.Tag.Lookup(tag); ok {
 		return d
 	}
-	return ","
+	return def
 }
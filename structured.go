@@ -0,0 +1,181 @@
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// NewJSONSource returns a FileSource holding the values in a JSON file
+// at the given path, which must hold an object.  Nested objects are
+// flattened into keys joined with underscores and upper-cased, so
+// {"db": {"host": "x"}} holds DB_HOST, and arrays are held as lists.
+// Null values are treated as unset, and it's an error for two values
+// to be held under the same key.
+func NewJSONSource(path string) (*FileSource, error) {
+	return newFileSource(func() (fv fileValues, err error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		return parseJSON(b)
+	})
+}
+
+// NewINISource returns a FileSource holding the values in an INI file
+// at the given path.  Keys are prefixed with the name of their
+// section, so host in the [db] section is held as DB_HOST, and keys
+// ending in [] are appended to lists, as in peers[] = a.  Other keys
+// may only be set once.  Lines beginning with ; or # are ignored, and
+// values may be quoted in the same way as in dotenv files.
+func NewINISource(path string) (*FileSource, error) {
+	return newFileSource(func() (fv fileValues, err error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		return parseINI(b)
+	})
+}
+
+// flatKey joins the parts of a nested key into a single key in the
+// style of an environment variable.
+func flatKey(parts ...string) string {
+	key := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(key)
+}
+
+func parseJSON(b []byte) (fv fileValues, err error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var obj map[string]interface{}
+	if err = d.Decode(&obj); err != nil {
+		return
+	}
+
+	fv = fileValues{values: map[string]string{}, lists: map[string][]string{}}
+	err = flattenJSON(fv, map[string]string{}, nil, obj)
+	return
+}
+
+// flattenJSON adds the values in obj to fv, where paths records the
+// path in the JSON of each key added so far, as different paths can
+// flatten to the same key.  Names are visited in order, so that errors
+// are consistent.
+func flattenJSON(fv fileValues, paths map[string]string, path []string, obj map[string]interface{}) error {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := append(path[:len(path):len(path)], name)
+
+		var flat string
+		switch v := obj[name].(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if err := flattenJSON(fv, paths, key, v); err != nil {
+				return err
+			}
+			continue
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				s, ok := jsonScalar(item)
+				if !ok {
+					return fmt.Errorf("%s: arrays may only hold strings, numbers and bools", strings.Join(key, "."))
+				}
+				items[i] = s
+			}
+			flat = flatKey(key...)
+			fv.lists[flat] = items
+		default:
+			s, _ := jsonScalar(v)
+			flat = flatKey(key...)
+			fv.values[flat] = s
+		}
+
+		if prev, ok := paths[flat]; ok {
+			return fmt.Errorf("%s and %s both hold %s", prev, strings.Join(key, "."), flat)
+		}
+		paths[flat] = strings.Join(key, ".")
+	}
+
+	return nil
+}
+
+// jsonScalar formats a string, number or bool decoded from JSON as it
+// would be written in the environment.
+func jsonScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+func parseINI(b []byte) (fv fileValues, err error) {
+	fv = fileValues{values: map[string]string{}, lists: map[string][]string{}}
+
+	// lines records the line that set each key, as different names
+	// can flatten to the same key.  Only list items may be repeated.
+	lines := map[string]int{}
+
+	var section []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, ok := strings.CutSuffix(line[1:], "]")
+			if !ok {
+				return fv, fmt.Errorf("line %d: expected [section]", n)
+			}
+			section = nil
+			if name = strings.TrimSpace(name); name != "" {
+				section = []string{name}
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fv, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if value, err = dotEnvUnquote(strings.TrimSpace(value)); err != nil {
+			return fv, fmt.Errorf("line %d: %v", n, err)
+		}
+
+		name, list := strings.CutSuffix(name, "[]")
+		key := flatKey(append(section, name)...)
+		_, isList := fv.lists[key]
+		if prev, ok := lines[key]; ok && !(list && isList) {
+			return fv, fmt.Errorf("line %d: %s is already set on line %d", n, key, prev)
+		}
+		lines[key] = n
+
+		if list {
+			fv.lists[key] = append(fv.lists[key], value)
+			continue
+		}
+		fv.values[key] = value
+	}
+
+	return fv, scanner.Err()
+}
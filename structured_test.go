@@ -0,0 +1,160 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+type structuredConfig struct {
+	Host    string        `env:"DB_HOST" required:"true"`
+	Port    int           `env:"DB_PORT" default:"5432"`
+	Timeout time.Duration `env:"DB_TIMEOUT"`
+	Peers   []string      `env:"PEERS" delimiter:";"`
+	Ports   []int         `env:"PORTS"`
+	Debug   bool          `env:"DEBUG"`
+	Ratio   float64       `env:"RATIO"`
+	Name    string        `env:"APP_NAME"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	ErrorNil(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestJSONSource(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+		"db": {"host": "localhost", "timeout": "5s", "port": null},
+		"peers": ["a;b", "c"],
+		"ports": [1, 2],
+		"debug": true,
+		"ratio": 1.50,
+		"app-name": "x"
+	}`)
+
+	src, err := NewJSONSource(path)
+	ErrorNil(t, err)
+
+	keys := src.Keys()
+	sort.Strings(keys)
+	Equals(t, []string{"APP_NAME", "DB_HOST", "DB_TIMEOUT", "DEBUG", "PEERS", "PORTS", "RATIO"}, keys)
+
+	v, ok := src.Lookup("PORTS")
+	Assert(t, ok)
+	Equals(t, "1,2", v)
+
+	var config structuredConfig
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, structuredConfig{
+		Host:    "localhost",
+		Port:    5432,
+		Timeout: 5 * time.Second,
+		Peers:   []string{"a", "b", "c"},
+		Ports:   []int{1, 2},
+		Debug:   true,
+		Ratio:   1.5,
+		Name:    "x",
+	}, config)
+}
+
+func TestJSONSourceRequired(t *testing.T) {
+	src, err := NewJSONSource(writeFile(t, "config.json", `{"db": {}}`))
+	ErrorNil(t, err)
+
+	var config structuredConfig
+	err = NewLoader(WithSource(src)).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "DB_HOST environment configuration was missing", err.Error())
+}
+
+func TestJSONSourceErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{name: "not an object", src: `[]`, err: "json: cannot unmarshal array into Go value of type map[string]interface {}"},
+		{name: "invalid", src: `{`, err: "unexpected EOF"},
+		{name: "nested array", src: `{"db": {"hosts": [{"a": 1}]}}`, err: "db.hosts: arrays may only hold strings, numbers and bools"},
+		{name: "duplicate", src: `{"db_host": "a", "db": {"host": "b"}}`, err: "db.host and db_host both hold DB_HOST"},
+		{name: "duplicate list", src: `{"peers": "a", "PEERS": ["b"]}`, err: "PEERS and peers both hold PEERS"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewJSONSource(writeFile(t, "config.json", testCase.src))
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestINISource(t *testing.T) {
+	path := writeFile(t, "config.ini", `; comment
+debug = true
+app-name = "x y" # comment
+peers[] = a;b
+peers[] = c
+
+[db]
+host = localhost
+timeout = 5s
+
+# comment
+[ ]
+ratio = 1.5
+`)
+
+	src, err := NewINISource(path)
+	ErrorNil(t, err)
+
+	var config structuredConfig
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, structuredConfig{
+		Host:    "localhost",
+		Port:    5432,
+		Timeout: 5 * time.Second,
+		Peers:   []string{"a", "b", "c"},
+		Debug:   true,
+		Ratio:   1.5,
+		Name:    "x y",
+	}, config)
+}
+
+func TestINISourceErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{name: "section", src: "[db", err: "line 1: expected [section]"},
+		{name: "assignment", src: "a = 1\nb", err: "line 2: expected key = value"},
+		{name: "quote", src: `a = "b`, err: "line 1: unterminated quoted value"},
+		{name: "duplicate", src: "db_host = a\n[db]\nhost = b", err: "line 3: DB_HOST is already set on line 1"},
+		{name: "repeated", src: "a = 1\nA = 2", err: "line 2: A is already set on line 1"},
+		{name: "list and value", src: "a[] = 1\na = 2", err: "line 2: A is already set on line 1"},
+		{name: "value and list", src: "a = 1\na[] = 2", err: "line 2: A is already set on line 1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewINISource(writeFile(t, "config.ini", testCase.src))
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestListSourceAliases(t *testing.T) {
+	src, err := NewJSONSource(writeFile(t, "config.json", `{"old": ["a", "b"]}`))
+	ErrorNil(t, err)
+
+	config := struct {
+		New []string `env:"NEW" aliases:"OLD" delimiter:"|"`
+	}{}
+	ErrorNil(t, NewLoader(WithSource(src)).Load(&config))
+	Equals(t, []string{"a", "b"}, config.New)
+}
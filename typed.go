@@ -0,0 +1,61 @@
+package env
+
+import (
+	"os"
+	"reflect"
+)
+
+// Parse returns a struct of type T, with its fields set by a Loader
+// configured with the given options.  Without options, fields are
+// set from environment config in the same way as Set.
+func Parse[T any](opts ...Option) (T, error) {
+	l := std
+	if len(opts) > 0 {
+		l = NewLoader(opts...)
+	}
+
+	var t T
+	err := l.Load(&t)
+	return t, err
+}
+
+// MustParse is like Parse but panics with the error if the struct
+// cannot be set.
+func MustParse[T any](opts ...Option) T {
+	t, err := Parse[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Get returns the value of a single environment variable, parsed into
+// a value of type T in the same way as a struct field of that type
+// would be by Set.  If the variable isn't found, def is returned.
+func Get[T any](key string, def T) (T, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def, nil
+	}
+
+	var t T
+	f := field{
+		sf:        reflect.StructField{Name: key, Type: reflect.TypeOf(&t).Elem()},
+		name:      key,
+		delimiter: ",",
+	}
+	if err := std.resolveSetter(f)(reflect.ValueOf(&t).Elem(), value); err != nil {
+		return def, err
+	}
+	return t, nil
+}
+
+// MustGet is like Get but panics with the error if the value cannot
+// be parsed.
+func MustGet[T any](key string, def T) T {
+	t, err := Get(key, def)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
@@ -0,0 +1,103 @@
+package env
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+type typedConfig struct {
+	Port  int      `env:"PORT" required:"true"`
+	Hosts []string `env:"HOSTS"`
+}
+
+func TestParse(t *testing.T) {
+	os.Setenv("TYPED_PORT", "1234")
+	os.Setenv("TYPED_HOSTS", "a,b")
+
+	c, err := Parse[typedConfig](WithPrefix("TYPED_"))
+	ErrorNil(t, err)
+	Equals(t, typedConfig{Port: 1234, Hosts: []string{"a", "b"}}, c)
+}
+
+func TestParseError(t *testing.T) {
+	os.Unsetenv("MISSING_PORT")
+
+	_, err := Parse[typedConfig](WithPrefix("MISSING_"))
+	ErrorNotNil(t, err)
+	Equals(t, "PORT environment configuration was missing", err.Error())
+}
+
+func TestParseNotStruct(t *testing.T) {
+	_, err := Parse[int]()
+	ErrorNotNil(t, err)
+	Equals(t, "int is not a struct", err.Error())
+
+	_, err = Parse[*typedConfig]()
+	ErrorNotNil(t, err)
+	Equals(t, "*env.typedConfig is not a struct", err.Error())
+}
+
+func TestMustParsePanics(t *testing.T) {
+	os.Unsetenv("MISSING_PORT")
+
+	defer func() {
+		err, ok := recover().(error)
+		Assert(t, ok)
+		Equals(t, "PORT environment configuration was missing", err.Error())
+	}()
+
+	MustParse[typedConfig](WithPrefix("MISSING_"))
+	t.Fatal("expected panic")
+}
+
+func TestGet(t *testing.T) {
+	os.Setenv("PROP", "1m30s")
+	os.Setenv("PROPS", "1, 2, 3")
+
+	d, err := Get("PROP", time.Second)
+	ErrorNil(t, err)
+	Equals(t, time.Minute+time.Second*30, d)
+
+	i, err := Get[[]int]("PROPS", nil)
+	ErrorNil(t, err)
+	Equals(t, []int{1, 2, 3}, i)
+}
+
+func TestGetDefault(t *testing.T) {
+	os.Unsetenv("PROP")
+
+	i, err := Get("PROP", 123)
+	ErrorNil(t, err)
+	Equals(t, 123, i)
+}
+
+func TestGetSetter(t *testing.T) {
+	os.Setenv("PROP", "3h2m1s")
+
+	d, err := Get[*configDuration]("PROP", nil)
+	ErrorNil(t, err)
+	Equals(t, time.Hour*3+time.Minute*2+time.Second*1, d.Duration)
+}
+
+func TestGetError(t *testing.T) {
+	os.Setenv("PROP", "hello")
+
+	i, err := Get("PROP", 123)
+	ErrorNotNil(t, err)
+	Equals(t, `error setting "PROP": strconv.ParseInt: parsing "hello": invalid syntax`, err.Error())
+	Equals(t, 123, i)
+}
+
+func TestMustGetPanics(t *testing.T) {
+	os.Setenv("PROP", "hello")
+
+	defer func() {
+		err, ok := recover().(error)
+		Assert(t, ok)
+		Equals(t, `error setting "PROP": strconv.ParseBool: parsing "hello": invalid syntax`, err.Error())
+	}()
+
+	MustGet("PROP", false)
+	t.Fatal("expected panic")
+}
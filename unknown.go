@@ -0,0 +1,149 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// KeyLister is implemented by Sources that can list the keys they
+// hold, which is required to check for unknown keys.
+type KeyLister interface {
+	Keys() []string
+}
+
+// MapSource is a Source holding its values in a map.
+type MapSource map[string]string
+
+// Lookup returns the value of key in m.
+func (m MapSource) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Keys returns the keys of m.
+func (m MapSource) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// environment is the Source used by default, which reads environment
+// variables.
+type environment struct{}
+
+func (environment) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (environment) Keys() (keys []string) {
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, k)
+	}
+	return
+}
+
+// unknown returns an error for each key in the source that begins
+// with the prefix but isn't read by any of the given fields, along
+// with the closest matching name if one looks like it was intended.
+func (l *Loader) unknown(prefix string, fs []field) error {
+	lister, ok := l.source.(KeyLister)
+	if !ok {
+		return fmt.Errorf("%T cannot list its keys to check for unknown configuration", l.source)
+	}
+
+	known := map[string]bool{}
+	var names []string
+	add := func(key string) {
+		if key != "" && !known[key] {
+			known[key] = true
+			names = append(names, key)
+		}
+	}
+
+	// Besides the fields' own names, keys are read to choose the
+	// profile and to check conditions that don't name a field.
+	add(l.profileVar)
+	for _, f := range fs {
+		for _, name := range append([]string{f.name}, f.aliases...) {
+			add(prefix + name)
+		}
+		for _, c := range []*condition{f.requiredIf, f.requiredUnless} {
+			if c != nil {
+				add(prefix + c.name)
+			}
+		}
+	}
+
+	var keys []string
+	for _, key := range lister.Keys() {
+		if strings.HasPrefix(key, prefix) && !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if s := suggest(key, names); s != "" {
+			errs = append(errs, fmt.Errorf("unknown %s configuration %s (did you mean %s?)", configTypeEnvironment, key, s))
+			continue
+		}
+		errs = append(errs, fmt.Errorf("unknown %s configuration %s", configTypeEnvironment, key))
+	}
+
+	return errors.Join(errs...)
+}
+
+// suggest returns the name closest to key, provided that it's close
+// enough to be a likely typo, or an empty string otherwise.
+func suggest(key string, names []string) (best string) {
+	// Allow roughly one mistake for every three characters.
+	limit := len(key) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	bestDist := limit + 1
+	for _, name := range names {
+		if d := distance(strings.ToUpper(key), strings.ToUpper(name)); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return
+}
+
+// distance returns the number of single-character insertions,
+// deletions, substitutions and transpositions of adjacent characters
+// needed to turn a into b.
+func distance(a, b string) int {
+	// d[i][j] holds the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
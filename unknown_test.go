@@ -0,0 +1,127 @@
+package env
+
+import (
+	"testing"
+)
+
+type unknownConfig struct {
+	Port    int    `env:"PORT" default:"8080"`
+	Host    string `env:"DATABASE_HOST" aliases:"DB_HOST"`
+	Verbose bool   `env:"VERBOSE"`
+}
+
+func TestDisallowUnknown(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source map[string]string
+		err    string
+	}{
+		{name: "known", source: map[string]string{"MYAPP_PORT": "1", "MYAPP_DB_HOST": "h", "OTHER": "x"}},
+		{name: "transposition", source: map[string]string{"MYAPP_PROT": "8081"}, err: "unknown environment configuration MYAPP_PROT (did you mean MYAPP_PORT?)"},
+		{name: "case", source: map[string]string{"MYAPP_verbose": "true"}, err: "unknown environment configuration MYAPP_verbose (did you mean MYAPP_VERBOSE?)"},
+		{name: "alias", source: map[string]string{"MYAPP_DB_HOTS": "h"}, err: "unknown environment configuration MYAPP_DB_HOTS (did you mean MYAPP_DB_HOST?)"},
+		{name: "no suggestion", source: map[string]string{"MYAPP_COLOUR": "red"}, err: "unknown environment configuration MYAPP_COLOUR"},
+		{name: "several", source: map[string]string{"MYAPP_PROT": "1", "MYAPP_COLOUR": "red"}, err: "unknown environment configuration MYAPP_COLOUR\nunknown environment configuration MYAPP_PROT (did you mean MYAPP_PORT?)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			l := NewLoader(
+				WithSource(MapSource(testCase.source)),
+				WithPrefix("MYAPP_"),
+				WithDisallowUnknown(),
+			)
+
+			var config unknownConfig
+			err := l.Load(&config)
+			if testCase.err == "" {
+				ErrorNil(t, err)
+				return
+			}
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestDisallowUnknownProfileVar(t *testing.T) {
+	l := NewLoader(
+		WithSource(MapSource{"MYAPP_ENV": "prod", "MYAPP_PORT": "1"}),
+		WithPrefix("MYAPP_"),
+		WithProfileVar("MYAPP_ENV"),
+		WithDisallowUnknown(),
+	)
+
+	var config unknownConfig
+	ErrorNil(t, l.Load(&config))
+
+	err := NewLoader(
+		WithSource(MapSource{"MYAPP_EVN": "prod"}),
+		WithPrefix("MYAPP_"),
+		WithProfileVar("MYAPP_ENV"),
+		WithDisallowUnknown(),
+	).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "unknown environment configuration MYAPP_EVN (did you mean MYAPP_ENV?)", err.Error())
+}
+
+func TestDisallowUnknownAggregated(t *testing.T) {
+	l := NewLoader(
+		WithSource(MapSource(map[string]string{"MYAPP_PROT": "1", "MYAPP_VERBOSE": "x"})),
+		WithPrefix("MYAPP_"),
+		WithDisallowUnknown(),
+		WithErrorAggregation(),
+	)
+
+	var config unknownConfig
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "unknown environment configuration MYAPP_PROT (did you mean MYAPP_PORT?)\n"+
+		`error setting "Verbose": strconv.ParseBool: parsing "x": invalid syntax`, err.Error())
+	Equals(t, 8080, config.Port)
+}
+
+func TestDisallowUnknownEnvironment(t *testing.T) {
+	unsetEnvironment()
+	defer unsetEnvironment()
+	t.Setenv("MYAPP_PROT", "8081")
+
+	var config unknownConfig
+	err := NewLoader(WithPrefix("MYAPP_"), WithDisallowUnknown()).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "unknown environment configuration MYAPP_PROT (did you mean MYAPP_PORT?)", err.Error())
+}
+
+func TestDisallowUnknownWithoutKeys(t *testing.T) {
+	l := NewLoader(
+		WithSource(SourceFunc(func(string) (string, bool) { return "", false })),
+		WithDisallowUnknown(),
+	)
+
+	var config unknownConfig
+	err := l.Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, "env.SourceFunc cannot list its keys to check for unknown configuration", err.Error())
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		exp  int
+	}{
+		{a: "", b: "", exp: 0},
+		{a: "PORT", b: "PORT", exp: 0},
+		{a: "PROT", b: "PORT", exp: 1},
+		{a: "POT", b: "PORT", exp: 1},
+		{a: "PORTS", b: "PORT", exp: 1},
+		{a: "PART", b: "PORT", exp: 1},
+		{a: "", b: "PORT", exp: 4},
+		{a: "HOST", b: "PORT", exp: 2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.a+"_"+testCase.b, func(t *testing.T) {
+			Equals(t, testCase.exp, distance(testCase.a, testCase.b))
+		})
+	}
+}
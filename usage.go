@@ -0,0 +1,151 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// usageEntry is the JSON representation of a single variable, as
+// written by UsageJSON.
+type usageEntry struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Default     *string `json:"default,omitempty"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Example     string  `json:"example,omitempty"`
+}
+
+// Usage writes an aligned table to w describing each of the
+// environment variables read by SetPrefix for the given struct, in
+// the style of flag.PrintDefaults.
+func Usage(w io.Writer, v interface{}, prefix string) (err error) {
+	entries, err := usageEntries(v, prefix)
+	if err != nil {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tREQUIRED\tEXAMPLE\tDESCRIPTION")
+	for _, e := range entries {
+		var d string
+		if e.Default != nil {
+			d = *e.Default
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\n", e.Name, e.Type, d, e.Required, e.Example, e.Description)
+	}
+
+	return tw.Flush()
+}
+
+// UsageMarkdown writes a Markdown table to w describing each of the
+// environment variables read by SetPrefix for the given struct.
+func UsageMarkdown(w io.Writer, v interface{}, prefix string) (err error) {
+	entries, err := usageEntries(v, prefix)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| Name | Type | Default | Required | Example | Description |\n")
+	sb.WriteString("|------|------|---------|----------|---------|-------------|\n")
+	for _, e := range entries {
+		var d string
+		if e.Default != nil {
+			d = markdownCode(*e.Default)
+		}
+		fmt.Fprintf(&sb, "| `%s` | `%s` | %s | %t | %s | %s |\n",
+			e.Name, e.Type, d, e.Required, markdownCode(e.Example), markdownEscape(e.Description))
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return
+}
+
+// UsageJSON writes a JSON array to w describing each of the
+// environment variables read by SetPrefix for the given struct.
+func UsageJSON(w io.Writer, v interface{}, prefix string) (err error) {
+	entries, err := usageEntries(v, prefix)
+	if err != nil {
+		return
+	}
+
+	// Encode an empty array rather than null if there's nothing to
+	// describe.
+	if entries == nil {
+		entries = []usageEntry{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func usageEntries(v interface{}, prefix string) (entries []usageEntry, err error) {
+	fs, err := fields(v, prefix)
+	if err != nil {
+		return
+	}
+
+	for _, f := range fs {
+		e := usageEntry{
+			Name:        f.name,
+			Type:        f.sf.Type.String(),
+			Required:    f.required,
+			Description: f.desc,
+			Example:     listExample(f),
+		}
+		if f.hasDefault {
+			d := f.def
+			e.Default = &d
+		}
+		entries = append(entries, e)
+	}
+
+	return
+}
+
+// listExample returns an example of the syntax expected for a list
+// field, using its delimiter, or an empty string for other fields.
+func listExample(f field) string {
+	if !f.isList() {
+		return ""
+	}
+
+	var samples []string
+	switch elem := f.sf.Type.Elem(); elem.Kind() {
+	case reflect.Bool:
+		samples = []string{"true", "false", "true"}
+	case reflect.Int64:
+		if elem == reflect.TypeOf(time.Duration(0)) {
+			samples = []string{"1s", "2m", "3h"}
+			break
+		}
+		samples = []string{"1", "2", "3"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		samples = []string{"1", "2", "3"}
+	case reflect.Float32, reflect.Float64:
+		samples = []string{"1.1", "2.2", "3.3"}
+	default:
+		samples = []string{"a", "b", "c"}
+	}
+
+	return strings.Join(samples, f.delimiter)
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
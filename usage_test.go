@@ -0,0 +1,86 @@
+package env
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type usageConfig struct {
+	Hosts   []string      `env:"HOSTS" required:"true" delimiter:";" desc:"Peers to connect to"`
+	Port    int           `env:"PORT" default:"8080" desc:"Port to listen on"`
+	Timeout time.Duration `env:"TIMEOUT" default:"1s"`
+	Ignored string
+}
+
+func TestUsage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, Usage(buf, usageConfig{}, "APP_"))
+
+	exp := strings.Join([]string{
+		"NAME         TYPE           DEFAULT  REQUIRED  EXAMPLE  DESCRIPTION",
+		"APP_HOSTS    []string                true      a;b;c    Peers to connect to",
+		"APP_PORT     int            8080     false              Port to listen on",
+		"APP_TIMEOUT  time.Duration  1s       false              ",
+		"",
+	}, "\n")
+	Equals(t, exp, buf.String())
+}
+
+func TestUsageMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, UsageMarkdown(buf, &usageConfig{}, ""))
+
+	exp := strings.Join([]string{
+		"| Name | Type | Default | Required | Example | Description |",
+		"|------|------|---------|----------|---------|-------------|",
+		"| `HOSTS` | `[]string` |  | true | `a;b;c` | Peers to connect to |",
+		"| `PORT` | `int` | `8080` | false |  | Port to listen on |",
+		"| `TIMEOUT` | `time.Duration` | `1s` | false |  |  |",
+		"",
+	}, "\n")
+	Equals(t, exp, buf.String())
+}
+
+func TestUsageJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ErrorNil(t, UsageJSON(buf, &struct {
+		Prop  []int `env:"PROP" default:"1,2" desc:"Some numbers"`
+		Other bool  `env:"OTHER" required:"true"`
+	}{}, ""))
+
+	exp := `[
+  {
+    "name": "PROP",
+    "type": "[]int",
+    "default": "1,2",
+    "required": false,
+    "description": "Some numbers",
+    "example": "1,2,3"
+  },
+  {
+    "name": "OTHER",
+    "type": "bool",
+    "required": true
+  }
+]
+`
+	Equals(t, exp, buf.String())
+}
+
+func TestUsageInvalidRequiredTag(t *testing.T) {
+	err := Usage(&bytes.Buffer{}, struct {
+		Prop int `env:"PROP" required:"invalid"`
+	}{}, "")
+
+	ErrorNotNil(t, err)
+	Equals(t, `invalid required tag "invalid": strconv.ParseBool: parsing "invalid": invalid syntax`, err.Error())
+}
+
+func TestUsageNonStruct(t *testing.T) {
+	err := Usage(&bytes.Buffer{}, 1, "")
+
+	ErrorNotNil(t, err)
+	Equals(t, "int is not a struct", err.Error())
+}
@@ -0,0 +1,185 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig configures a Vault.
+type VaultConfig struct {
+	// Addr is the address of the Vault server, such as
+	// https://vault.internal:8200.
+	Addr string
+
+	// Token authenticates requests, and Namespace is sent as the
+	// X-Vault-Namespace header, if set.
+	Token     string
+	Namespace string
+
+	// Client is used to make requests, defaulting to
+	// http.DefaultClient.
+	Client *http.Client
+
+	// Timeout limits each request, defaulting to ten seconds.
+	Timeout time.Duration
+
+	// TTL is how long secrets are cached for when Vault doesn't give
+	// them a lease, as is usual for the KV engine.  By default, they're
+	// cached until Reload is called.
+	TTL time.Duration
+}
+
+// Vault reads secrets from a Vault KV version 2 secrets engine, for
+// fields tagged with the path of a secret and the key of the value
+// within it, such as vault:"secret/data/db#password".  Each secret is
+// read once and cached until its lease expires.
+type Vault struct {
+	ctx context.Context
+	cfg VaultConfig
+
+	mu      sync.Mutex
+	secrets map[string]vaultSecret
+}
+
+// vaultSecret is the cached data of a secret.
+type vaultSecret struct {
+	data    map[string]string
+	expires time.Time
+}
+
+// NewVault returns a Vault configured by cfg, which makes requests
+// with the given context.
+func NewVault(ctx context.Context, cfg VaultConfig) *Vault {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &Vault{ctx: ctx, cfg: cfg, secrets: map[string]vaultSecret{}}
+}
+
+// WithVault causes the Loader to set fields tagged with the location
+// of a secret from v, in preference to its source.  If the secret or
+// its key doesn't exist, the field is set from the source as usual.
+func WithVault(v *Vault) Option {
+	return func(l *Loader) {
+		l.vault = v
+	}
+}
+
+// Reload clears the cache, so that secrets are read again.
+func (v *Vault) Reload() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.secrets = map[string]vaultSecret{}
+	return nil
+}
+
+// Read returns the value of a key within a secret, given as path#key.
+func (v *Vault) Read(ref string) (value string, ok bool, err error) {
+	path, key, err := parseVaultRef(ref)
+	if err != nil {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s, cached := v.secrets[path]
+	if !cached || (!s.expires.IsZero() && time.Now().After(s.expires)) {
+		if s, err = v.read(path); err != nil {
+			return
+		}
+		v.secrets[path] = s
+	}
+
+	value, ok = s.data[key]
+	return
+}
+
+// parseVaultRef splits the location of a value into the path of its
+// secret and its key.
+func parseVaultRef(ref string) (path, key string, err error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", "", fmt.Errorf("invalid vault tag %q: expected path#key", ref)
+	}
+	return strings.Trim(path, "/"), key, nil
+}
+
+// read fetches a secret, returning no data if it doesn't exist.
+// Values are never included in errors.
+func (v *Vault) read(path string) (s vaultSecret, err error) {
+	ctx, cancel := context.WithTimeout(v.ctx, v.cfg.Timeout)
+	defer cancel()
+
+	u := strings.TrimSuffix(v.cfg.Addr, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+
+	resp, err := v.cfg.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return v.expiring(vaultSecret{}, 0), nil
+	default:
+		return s, fmt.Errorf("error reading vault secret %q: unexpected status %s", path, resp.Status)
+	}
+
+	var body struct {
+		LeaseDuration int `json:"lease_duration"`
+		Data          struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return s, fmt.Errorf("error reading vault secret %q: %v", path, err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body.Data.Data))
+	d.UseNumber()
+	var data map[string]interface{}
+	if err = d.Decode(&data); err != nil {
+		return s, fmt.Errorf("error reading vault secret %q: %v", path, err)
+	}
+
+	s.data = map[string]string{}
+	for k, item := range data {
+		if value, ok := jsonScalar(item); ok {
+			s.data[k] = value
+		}
+	}
+	return v.expiring(s, time.Duration(body.LeaseDuration)*time.Second), nil
+}
+
+// expiring sets the time a secret should be read again, after its
+// lease or the configured TTL.
+func (v *Vault) expiring(s vaultSecret, lease time.Duration) vaultSecret {
+	if lease == 0 {
+		lease = v.cfg.TTL
+	}
+	if lease > 0 {
+		s.expires = time.Now().Add(lease)
+	}
+	return s
+}
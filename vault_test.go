@@ -0,0 +1,159 @@
+package env
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeVault serves KV version 2 secrets, counting the reads of each.
+type fakeVault struct {
+	*httptest.Server
+	secrets map[string]string
+	lease   int
+	reads   atomic.Int32
+}
+
+func newFakeVault(t *testing.T, secrets map[string]string) *fakeVault {
+	v := &fakeVault{secrets: secrets}
+	v.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v.reads.Add(1)
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "ns" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		data, ok := v.secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"lease_duration": ` + strconv.Itoa(v.lease) + `, "data": {"data": ` + data + `, "metadata": {"version": 1}}}`))
+	}))
+	t.Cleanup(v.Close)
+	return v
+}
+
+type vaultConfig struct {
+	User     string `env:"DB_USER" vault:"secret/data/db#username"`
+	Password string `env:"DB_PASSWORD" vault:"secret/data/db#password" required:"true"`
+	Port     int    `env:"DB_PORT" vault:"secret/data/db#port" default:"1"`
+	Missing  string `env:"MISSING" vault:"secret/data/missing#key" default:"d"`
+	Plain    string `env:"PLAIN"`
+}
+
+func TestVault(t *testing.T) {
+	fake := newFakeVault(t, map[string]string{
+		"/v1/secret/data/db": `{"username": "admin", "password": "hunter2", "port": 5432}`,
+	})
+	v := NewVault(context.Background(), VaultConfig{Addr: fake.URL, Token: "token", Namespace: "ns"})
+
+	var config vaultConfig
+	l := NewLoader(
+		WithSource(MapSource{"DB_USER": "env", "PLAIN": "p"}),
+		WithVault(v),
+	)
+	ErrorNil(t, l.Load(&config))
+	Equals(t, vaultConfig{User: "admin", Password: "hunter2", Port: 5432, Missing: "d", Plain: "p"}, config)
+
+	// Each secret is read once.
+	Equals(t, int32(2), fake.reads.Load())
+	ErrorNil(t, l.Load(&config))
+	Equals(t, int32(2), fake.reads.Load())
+
+	// Until the cache is cleared.
+	fake.secrets["/v1/secret/data/db"] = `{"password": "changed"}`
+	ErrorNil(t, v.Reload())
+	config = vaultConfig{}
+	ErrorNil(t, l.Load(&config))
+	Equals(t, vaultConfig{User: "env", Password: "changed", Port: 1, Missing: "d", Plain: "p"}, config)
+}
+
+func TestVaultLease(t *testing.T) {
+	fake := newFakeVault(t, map[string]string{"/v1/secret/data/db": `{"password": "a"}`})
+	v := NewVault(context.Background(), VaultConfig{Addr: fake.URL, Token: "token", Namespace: "ns"})
+
+	value, ok, err := v.Read("secret/data/db#password")
+	ErrorNil(t, err)
+	Assert(t, ok)
+	Equals(t, "a", value)
+
+	// Secrets without a lease are cached until reloaded.
+	fake.secrets["/v1/secret/data/db"] = `{"password": "b"}`
+	value, _, _ = v.Read("secret/data/db#password")
+	Equals(t, "a", value)
+	Equals(t, int32(1), fake.reads.Load())
+
+	// Secrets with a lease are read again once it expires.
+	fake.lease = 3600
+	ErrorNil(t, v.Reload())
+	value, _, _ = v.Read("secret/data/db#password")
+	Equals(t, "b", value)
+
+	v.mu.Lock()
+	s := v.secrets["secret/data/db"]
+	Assert(t, time.Until(s.expires) > 59*time.Minute)
+	s.expires = time.Now().Add(-time.Second)
+	v.secrets["secret/data/db"] = s
+	v.mu.Unlock()
+
+	fake.secrets["/v1/secret/data/db"] = `{"password": "c"}`
+	value, _, err = v.Read("secret/data/db#password")
+	ErrorNil(t, err)
+	Equals(t, "c", value)
+	Equals(t, int32(3), fake.reads.Load())
+}
+
+func TestVaultTTL(t *testing.T) {
+	fake := newFakeVault(t, map[string]string{"/v1/secret/data/db": `{"password": "a"}`})
+	v := NewVault(context.Background(), VaultConfig{Addr: fake.URL, Token: "token", Namespace: "ns", TTL: time.Nanosecond})
+
+	v.Read("secret/data/db#password")
+	time.Sleep(time.Millisecond)
+	v.Read("secret/data/db#password")
+	Equals(t, int32(2), fake.reads.Load())
+}
+
+func TestVaultErrors(t *testing.T) {
+	fake := newFakeVault(t, map[string]string{"/v1/secret/data/bad": `"x"`})
+
+	testCases := []struct {
+		name string
+		cfg  VaultConfig
+		ref  string
+		err  string
+	}{
+		{name: "forbidden", cfg: VaultConfig{Addr: fake.URL, Token: "wrong"}, ref: "secret/data/db#password", err: `error reading vault secret "secret/data/db": unexpected status 403 Forbidden`},
+		{name: "invalid data", cfg: VaultConfig{Addr: fake.URL, Token: "token", Namespace: "ns"}, ref: "secret/data/bad#password", err: `error reading vault secret "secret/data/bad": json: cannot unmarshal string into Go value of type map[string]interface {}`},
+		{name: "invalid ref", cfg: VaultConfig{Addr: fake.URL}, ref: "secret/data/db", err: `invalid vault tag "secret/data/db": expected path#key`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, err := NewVault(context.Background(), testCase.cfg).Read(testCase.ref)
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+		})
+	}
+}
+
+func TestVaultLoadErrors(t *testing.T) {
+	fake := newFakeVault(t, nil)
+	v := NewVault(context.Background(), VaultConfig{Addr: fake.URL, Token: "wrong"})
+
+	var config vaultConfig
+	err := NewLoader(WithSource(MapSource{}), WithVault(v)).Load(&config)
+	ErrorNotNil(t, err)
+	Equals(t, `error reading vault secret "secret/data/db": unexpected status 403 Forbidden`, err.Error())
+
+	invalid := struct {
+		Password string `env:"PASSWORD" vault:"secret/data/db"`
+	}{}
+	err = NewLoader(WithSource(MapSource{}), WithVault(v)).Load(&invalid)
+	ErrorNotNil(t, err)
+	Equals(t, `invalid vault tag "secret/data/db": expected path#key`, err.Error())
+}
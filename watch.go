@@ -0,0 +1,221 @@
+package env
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Validator is implemented by configuration structs that need checks
+// beyond those expressed in their tags.  Watch calls Validate on each
+// newly loaded struct before publishing it.
+type Validator interface {
+	Validate() error
+}
+
+// Watcher holds configuration that's reloaded while a program runs.
+// It's safe for concurrent use.
+type Watcher[T any] struct {
+	loader *Loader
+	source Source
+
+	current atomic.Pointer[T]
+
+	// mu serialises reloads and guards the callbacks, which are called
+	// without it held, along with the notifications waiting to be
+	// passed to them.  notifying is set while they're being called.
+	mu          sync.Mutex
+	subscribers []func(old, new *T)
+	onError     []func(error)
+	pending     []notification[T]
+	notifying   bool
+}
+
+// Watch loads a T from the given source, as Parse does, and then
+// reloads it every interval (if positive) and whenever the process
+// receives SIGHUP, until ctx is done.  Sources implementing Reloader, such as those
+// returned by NewDotEnvSource and NewDirSource, are re-read first.
+//
+// Each reload is fully loaded, with all of its errors aggregated, and
+// validated before being published, so readers only ever see complete
+// and valid configuration.  If a reload fails, the previous
+// configuration is kept and the error is passed to the functions
+// registered with OnError.
+func Watch[T any](ctx context.Context, src Source, interval time.Duration, opts ...Option) (*Watcher[T], error) {
+	opts = append([]Option{WithSource(src)}, opts...)
+	w := &Watcher[T]{
+		loader: NewLoader(append(opts, WithErrorAggregation())...),
+		source: src,
+	}
+
+	c, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(c)
+
+	// Signals are subscribed to before returning, so that none are
+	// missed (or left to terminate the process).
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go w.watch(ctx, interval, hup)
+	return w, nil
+}
+
+// Load returns the current configuration, which must not be modified.
+func (w *Watcher[T]) Load() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers a function to be called with the previous and
+// new configuration each time a reload changes it.
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// OnError registers a function to be called with the error each time
+// a reload fails.
+func (w *Watcher[T]) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onError = append(w.onError, fn)
+}
+
+// Diff compares two configurations loaded by the Watcher, as
+// Loader.Diff does for a Loader configured with the Watcher's options.
+func (w *Watcher[T]) Diff(old, new *T) ([]Change, error) {
+	return w.loader.Diff(old, new)
+}
+
+// Reload reloads the configuration immediately, publishing it and
+// notifying subscribers if it's changed.  On failure, the previous
+// configuration is kept and the error is returned, as well as being
+// passed to the functions registered with OnError.
+//
+// Callbacks are called in the order that reloads happen, without the
+// Watcher's lock held, so they're free to call its methods.  If
+// callbacks are already being called for another reload, including
+// from within one of them, Reload queues its own and returns without
+// waiting for them.
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	n, changed := w.reload()
+	if changed || n.err != nil {
+		w.pending = append(w.pending, n)
+	}
+	if w.notifying {
+		w.mu.Unlock()
+		return n.err
+	}
+	w.notifying = true
+	w.mu.Unlock()
+
+	w.notify()
+	return n.err
+}
+
+// notification records the outcome of a reload, to be passed to the
+// Watcher's callbacks.
+type notification[T any] struct {
+	old, new *T
+	err      error
+}
+
+// reload loads and publishes the configuration, returning false if it
+// hasn't changed.  The caller must hold the lock.
+func (w *Watcher[T]) reload() (n notification[T], changed bool) {
+	c, err := w.load()
+	if err != nil {
+		return notification[T]{err: err}, false
+	}
+
+	old := w.current.Load()
+	if reflect.DeepEqual(old, c) {
+		return notification[T]{}, false
+	}
+	w.current.Store(c)
+	return notification[T]{old: old, new: c}, true
+}
+
+// notify calls the callbacks for each pending notification in turn,
+// until there are none left.
+func (w *Watcher[T]) notify() {
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.notifying = false
+			w.mu.Unlock()
+			return
+		}
+		n := w.pending[0]
+		w.pending = w.pending[1:]
+		subscribers := slices.Clone(w.subscribers)
+		onError := slices.Clone(w.onError)
+		w.mu.Unlock()
+
+		if n.err != nil {
+			for _, fn := range onError {
+				fn(n.err)
+			}
+			continue
+		}
+		for _, fn := range subscribers {
+			fn(n.old, n.new)
+		}
+	}
+}
+
+func (w *Watcher[T]) load() (*T, error) {
+	if r, ok := w.source.(Reloader); ok {
+		if err := r.Reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	c := new(T)
+	if err := w.loader.Load(c); err != nil {
+		return nil, err
+	}
+	if v, ok := any(c).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (w *Watcher[T]) watch(ctx context.Context, interval time.Duration, hup chan os.Signal) {
+	defer signal.Stop(hup)
+
+	// Without a positive interval, reloads are only triggered by
+	// SIGHUP or by calling Reload.
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-hup:
+		}
+
+		// Errors are reported to the OnError functions.
+		w.Reload()
+	}
+}
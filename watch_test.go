@@ -0,0 +1,213 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type watchConfig struct {
+	Level string `env:"LEVEL" default:"info"`
+	Rate  int    `env:"RATE"`
+}
+
+func (c *watchConfig) Validate() error {
+	if c.Rate < 0 {
+		return errors.New("rate must not be negative")
+	}
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=1\n"), 0600))
+
+	src, err := NewDotEnvSource(path)
+	ErrorNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, src, 0)
+	ErrorNil(t, err)
+	Equals(t, watchConfig{Level: "info", Rate: 1}, *w.Load())
+
+	var changes [][2]watchConfig
+	w.Subscribe(func(old, new *watchConfig) {
+		changes = append(changes, [2]watchConfig{*old, *new})
+	})
+	var errs []error
+	w.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	// Unchanged values don't notify subscribers.
+	ErrorNil(t, w.Reload())
+	Equals(t, 0, len(changes))
+
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=2\nLEVEL=debug\n"), 0600))
+	ErrorNil(t, w.Reload())
+	Equals(t, watchConfig{Level: "debug", Rate: 2}, *w.Load())
+	Equals(t, [][2]watchConfig{{{Level: "info", Rate: 1}, {Level: "debug", Rate: 2}}}, changes)
+
+	// Failed reloads keep the previous configuration.
+	testCases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{name: "invalid file", src: "RATE", err: "line 1: expected KEY=VALUE"},
+		{name: "invalid value", src: "RATE=x", err: `error setting "Rate": strconv.ParseInt: parsing "x": invalid syntax`},
+		{name: "invalid config", src: "RATE=-1", err: "rate must not be negative"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs = nil
+			ErrorNil(t, os.WriteFile(path, []byte(testCase.src), 0600))
+
+			err := w.Reload()
+			ErrorNotNil(t, err)
+			Equals(t, testCase.err, err.Error())
+			Equals(t, []error{err}, errs)
+			Equals(t, watchConfig{Level: "debug", Rate: 2}, *w.Load())
+			Equals(t, 1, len(changes))
+		})
+	}
+}
+
+func TestWatchInitialError(t *testing.T) {
+	_, err := Watch[watchConfig](context.Background(), MapSource{"RATE": "-1"}, 0)
+	ErrorNotNil(t, err)
+	Equals(t, "rate must not be negative", err.Error())
+}
+
+func TestWatchInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=1\n"), 0600))
+
+	src, err := NewDotEnvSource(path)
+	ErrorNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, src, time.Millisecond)
+	ErrorNil(t, err)
+
+	changed := make(chan *watchConfig, 1)
+	w.Subscribe(func(old, new *watchConfig) {
+		changed <- new
+	})
+
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=2\n"), 0600))
+	select {
+	case c := <-changed:
+		Equals(t, 2, c.Rate)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}
+
+func TestWatchOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, MapSource{"APP_RATE": "3"}, 0, WithPrefix("APP_"))
+	ErrorNil(t, err)
+	Equals(t, 3, w.Load().Rate)
+}
+
+func TestWatchReentrantCallbacks(t *testing.T) {
+	src := MapSource{"RATE": "1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, src, 0)
+	ErrorNil(t, err)
+
+	// Callbacks may call the Watcher's methods without deadlocking.
+	done := make(chan struct{})
+	w.OnError(func(err error) {
+		w.OnError(func(error) {})
+		src["RATE"] = "3"
+		ErrorNil(t, w.Reload())
+	})
+	w.Subscribe(func(old, new *watchConfig) {
+		w.Subscribe(func(old, new *watchConfig) {})
+		Equals(t, 3, w.Load().Rate)
+		close(done)
+	})
+
+	go func() {
+		src["RATE"] = "-1"
+		w.Reload()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}
+
+func TestWatchNotificationOrder(t *testing.T) {
+	var rate atomic.Int32
+	src := SourceFunc(func(key string) (string, bool) {
+		if key != "RATE" {
+			return "", false
+		}
+		return strconv.Itoa(int(rate.Add(1))), true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, src, 0)
+	ErrorNil(t, err)
+
+	var mu sync.Mutex
+	var changes [][2]int
+	w.Subscribe(func(old, new *watchConfig) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		changes = append(changes, [2]int{old.Rate, new.Rate})
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ErrorNil(t, w.Reload())
+		}()
+	}
+	wg.Wait()
+
+	// Reloads may return before their subscribers are called.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(changes)
+		mu.Unlock()
+		if n == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d changes, expected 10", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Each change follows on from the one before it.
+	for i := 1; i < len(changes); i++ {
+		Equals(t, changes[i-1][1], changes[i][0])
+	}
+}
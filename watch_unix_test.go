@@ -0,0 +1,40 @@
+//go:build unix
+
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchSIGHUP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=1\n"), 0600))
+
+	src, err := NewDotEnvSource(path)
+	ErrorNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := Watch[watchConfig](ctx, src, 0)
+	ErrorNil(t, err)
+
+	changed := make(chan *watchConfig, 1)
+	w.Subscribe(func(old, new *watchConfig) {
+		changed <- new
+	})
+
+	ErrorNil(t, os.WriteFile(path, []byte("RATE=2\n"), 0600))
+	ErrorNil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	select {
+	case c := <-changed:
+		Equals(t, 2, c.Rate)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}